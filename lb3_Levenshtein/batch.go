@@ -0,0 +1,132 @@
+package main
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"lb3_Levenshtein/vagner_fisher"
+)
+
+// pairOptions are the settings of a global distance that are shared by the
+// single pair and every pair of a batch.
+type pairOptions struct {
+	algorithm   string
+	maxDistance int
+	seg         vagner_fisher.Segmentation
+	variant     vagner_fisher.Transposition
+	gaps        *vagner_fisher.AffineGaps
+	costs       vagner_fisher.CostModel
+	log         vagner_fisher.Logger
+}
+
+// pairResult holds the distance of a pair. Exceeded is set when the distance
+// is over maxDistance; Distance is not known then. Script is only set by the
+// full algorithm.
+type pairResult struct {
+	Distance   int
+	Exceeded   bool
+	Operations string
+	Script     vagner_fisher.EditScript
+}
+
+func computePair(s1, s2 string, opts pairOptions) pairResult {
+	var result pairResult
+
+	if opts.maxDistance >= 0 {
+		distance, ok := vagner_fisher.FindLevenshteinDistanceBounded(s1, s2, opts.seg, opts.maxDistance, opts.costs, opts.log)
+		result.Distance, result.Exceeded = distance, !ok
+		return result
+	}
+
+	switch opts.algorithm {
+	case "linear":
+		result.Distance = vagner_fisher.FindLevenshteinDistanceLinear(s1, s2, opts.seg, opts.costs, opts.log)
+	case "hirschberg":
+		result.Distance, result.Operations = vagner_fisher.FindLevenshteinDistanceHirschberg(s1, s2, opts.seg, opts.costs, opts.log)
+	default:
+		if opts.gaps != nil {
+			result.Distance, result.Script = vagner_fisher.FindAffineEditScript(s1, s2, opts.seg, *opts.gaps, opts.costs, opts.log)
+		} else {
+			result.Distance, result.Script = vagner_fisher.FindEditScript(s1, s2, opts.seg, opts.variant, opts.costs, opts.log)
+		}
+		result.Operations = result.Script.String()
+	}
+
+	return result
+}
+
+type batchRecord struct {
+	S1 string `json:"s1"`
+	S2 string `json:"s2"`
+}
+
+type batchResult struct {
+	S1         string `json:"s1"`
+	S2         string `json:"s2"`
+	Distance   *int   `json:"distance"`
+	Exceeded   bool   `json:"exceeded,omitempty"`
+	Operations string `json:"operations,omitempty"`
+}
+
+// runBatch computes the distance of every pair read from r, one per line:
+// two tab-separated strings in "tsv" format or an object with "s1" and "s2"
+// in "jsonl" format. Results are written in the same format, a distance over
+// the bound as ">k" in TSV and as null with "exceeded" in JSONL. It reports
+// whether any pair exceeded the bound.
+func runBatch(r io.Reader, w io.Writer, format string, opts pairOptions) (bool, error) {
+	encoder := json.NewEncoder(w)
+	encoder.SetEscapeHTML(false)
+
+	exceeded := false
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(nil, 1<<24)
+	for line := 1; scanner.Scan(); line++ {
+		text := strings.TrimSuffix(scanner.Text(), "\r")
+		if strings.TrimSpace(text) == "" {
+			continue
+		}
+
+		var record batchRecord
+		if format == "jsonl" {
+			if err := json.Unmarshal([]byte(text), &record); err != nil {
+				return exceeded, fmt.Errorf("line %d: %w", line, err)
+			}
+		} else {
+			fields := strings.Split(text, "\t")
+			if len(fields) != 2 {
+				return exceeded, fmt.Errorf("line %d: expected 2 tab-separated strings, got %d", line, len(fields))
+			}
+			record = batchRecord{fields[0], fields[1]}
+		}
+
+		if err := vagner_fisher.ValidateCosts(record.S1, record.S2, opts.seg, opts.variant, opts.costs); err != nil {
+			return exceeded, fmt.Errorf("line %d: %w", line, err)
+		}
+		result := computePair(record.S1, record.S2, opts)
+		exceeded = exceeded || result.Exceeded
+
+		var err error
+		if format == "jsonl" {
+			out := batchResult{S1: record.S1, S2: record.S2, Exceeded: result.Exceeded, Operations: result.Operations}
+			if !result.Exceeded {
+				out.Distance = &result.Distance
+			}
+			err = encoder.Encode(out)
+		} else {
+			distance := strconv.Itoa(result.Distance)
+			if result.Exceeded {
+				distance = ">" + strconv.Itoa(opts.maxDistance)
+			}
+			_, err = fmt.Fprintf(w, "%s\t%s\t%s\t%s\n", record.S1, record.S2, distance, result.Operations)
+		}
+		if err != nil {
+			return exceeded, err
+		}
+	}
+
+	return exceeded, scanner.Err()
+}
@@ -0,0 +1,180 @@
+package bktree
+
+import (
+	"container/heap"
+	"math"
+	"slices"
+
+	"lb3_Levenshtein/vagner_fisher"
+)
+
+// DistanceFunc must be a metric for the tree to find every match: symmetric
+// and obeying the triangle inequality. Levenshtein costs are a metric when
+// insert and delete cost the same and replace costs are symmetric.
+type DistanceFunc func(s1, s2 string) int
+
+type node struct {
+	word     string
+	children map[int]*node
+}
+
+// Tree is a Burkhard-Keller tree: the child of a node under key k holds the
+// words at distance exactly k from it, so by the triangle inequality a query
+// within radius r of the node's distance d only has to visit keys in
+// [d-r, d+r].
+type Tree struct {
+	root     *node
+	distance DistanceFunc
+	size     int
+}
+
+type Result struct {
+	Word     string
+	Distance int
+}
+
+func New(distance DistanceFunc) *Tree {
+	if distance == nil {
+		distance = vagner_fisher.LevenshteinDistanceFunc(vagner_fisher.UnitCosts[string]{})
+	}
+	return &Tree{distance: distance}
+}
+
+func Build(words []string, distance DistanceFunc) *Tree {
+	t := New(distance)
+	for _, word := range words {
+		t.Insert(word)
+	}
+	return t
+}
+
+func (t *Tree) Len() int {
+	return t.size
+}
+
+// Insert adds word and reports whether it was not in the tree yet.
+func (t *Tree) Insert(word string) bool {
+	if t.root == nil {
+		t.root = &node{word: word}
+		t.size++
+		return true
+	}
+
+	cur := t.root
+	for {
+		d := t.distance(word, cur.word)
+		if d == 0 && word == cur.word {
+			return false
+		}
+		child, ok := cur.children[d]
+		if !ok {
+			if cur.children == nil {
+				cur.children = make(map[int]*node)
+			}
+			cur.children[d] = &node{word: word}
+			t.size++
+			return true
+		}
+		cur = child
+	}
+}
+
+func sortResults(results []Result) {
+	slices.SortFunc(results, func(x, y Result) int {
+		if x.Distance != y.Distance {
+			return x.Distance - y.Distance
+		}
+		if x.Word < y.Word {
+			return -1
+		}
+		if x.Word > y.Word {
+			return 1
+		}
+		return 0
+	})
+}
+
+// Within returns every word at distance at most radius from query, closest
+// first.
+func (t *Tree) Within(query string, radius int) []Result {
+	var results []Result
+	if t.root == nil {
+		return results
+	}
+
+	stack := []*node{t.root}
+	for len(stack) > 0 {
+		cur := stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
+
+		d := t.distance(query, cur.word)
+		if d <= radius {
+			results = append(results, Result{cur.word, d})
+		}
+		for key, child := range cur.children {
+			if max(key-d, d-key) <= radius {
+				stack = append(stack, child)
+			}
+		}
+	}
+
+	sortResults(results)
+	return results
+}
+
+// resultHeap keeps the k best results found so far with the worst on top.
+type resultHeap []Result
+
+func (h resultHeap) Len() int { return len(h) }
+func (h resultHeap) Less(i, j int) bool {
+	if h[i].Distance != h[j].Distance {
+		return h[i].Distance > h[j].Distance
+	}
+	return h[i].Word > h[j].Word
+}
+func (h resultHeap) Swap(i, j int) { h[i], h[j] = h[j], h[i] }
+func (h *resultHeap) Push(x any)   { *h = append(*h, x.(Result)) }
+func (h *resultHeap) Pop() any {
+	old := *h
+	last := old[len(old)-1]
+	*h = old[:len(old)-1]
+	return last
+}
+
+// Nearest returns the k words closest to query, closest first. Ties at the
+// k-th distance are broken by the word.
+func (t *Tree) Nearest(query string, k int) []Result {
+	best := &resultHeap{}
+	if t.root == nil || k <= 0 {
+		return nil
+	}
+
+	stack := []*node{t.root}
+	for len(stack) > 0 {
+		cur := stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
+
+		d := t.distance(query, cur.word)
+		candidate := Result{cur.word, d}
+		if best.Len() < k {
+			heap.Push(best, candidate)
+		} else if top := (*best)[0]; d < top.Distance || (d == top.Distance && cur.word < top.Word) {
+			(*best)[0] = candidate
+			heap.Fix(best, 0)
+		}
+
+		radius := math.MaxInt
+		if best.Len() == k {
+			radius = (*best)[0].Distance
+		}
+		for key, child := range cur.children {
+			if max(key-d, d-key) <= radius {
+				stack = append(stack, child)
+			}
+		}
+	}
+
+	results := slices.Clone(*best)
+	sortResults(results)
+	return results
+}
@@ -0,0 +1,81 @@
+package bktree
+
+import (
+	"math/rand"
+	"slices"
+	"testing"
+)
+
+func randomWord(r *rand.Rand) string {
+	word := make([]rune, r.Intn(7))
+	for k := range word {
+		word[k] = rune('a' + r.Intn(4))
+	}
+	return string(word)
+}
+
+// bruteForce returns every word with its distance to query, closest first.
+func bruteForce(t *Tree, words []string, query string) []Result {
+	var results []Result
+	for _, word := range words {
+		results = append(results, Result{word, t.distance(query, word)})
+	}
+	sortResults(results)
+	return results
+}
+
+func TestQueriesMatchBruteForce(t *testing.T) {
+	r := rand.New(rand.NewSource(2))
+	var words []string
+	for range 500 {
+		words = append(words, randomWord(r))
+	}
+	tree := Build(words, nil)
+
+	unique := slices.Compact(slices.Sorted(slices.Values(words)))
+	if tree.Len() != len(unique) {
+		t.Fatalf("Len = %d, want %d distinct words", tree.Len(), len(unique))
+	}
+
+	for range 300 {
+		query := randomWord(r)
+		all := bruteForce(tree, unique, query)
+
+		radius := r.Intn(4)
+		var within []Result
+		for _, result := range all {
+			if result.Distance <= radius {
+				within = append(within, result)
+			}
+		}
+		if got := tree.Within(query, radius); !slices.Equal(got, within) {
+			t.Errorf("Within(%q, %d) = %v, want %v", query, radius, got, within)
+		}
+
+		k := 1 + r.Intn(10)
+		if got := tree.Nearest(query, k); !slices.Equal(got, all[:k]) {
+			t.Errorf("Nearest(%q, %d) = %v, want %v", query, k, got, all[:k])
+		}
+	}
+}
+
+func TestEmptyAndDuplicates(t *testing.T) {
+	tree := New(nil)
+	if got := tree.Within("abc", 3); len(got) != 0 {
+		t.Errorf("Within on an empty tree = %v", got)
+	}
+	if got := tree.Nearest("abc", 3); len(got) != 0 {
+		t.Errorf("Nearest on an empty tree = %v", got)
+	}
+
+	if !tree.Insert("abc") || tree.Insert("abc") || !tree.Insert("abd") {
+		t.Errorf("Insert does not report duplicates")
+	}
+	want := []Result{{"abc", 0}, {"abd", 1}}
+	if got := tree.Nearest("abc", 5); !slices.Equal(got, want) {
+		t.Errorf("Nearest(abc, 5) = %v, want %v", got, want)
+	}
+	if got := tree.Nearest("abc", 0); len(got) != 0 {
+		t.Errorf("Nearest(abc, 0) = %v, want none", got)
+	}
+}
@@ -0,0 +1,255 @@
+package filediff
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"unicode/utf8"
+
+	"lb3_Levenshtein/logger"
+	"lb3_Levenshtein/render"
+	"lb3_Levenshtein/vagner_fisher"
+)
+
+type Options struct {
+	Context int
+	// MaxCells is the largest len(a)*len(b) aligned with the full DP. Larger
+	// inputs fall back to Hirschberg's linear-memory alignment.
+	MaxCells  int
+	CharEdits bool
+	// MinSimilarity is how similar a replaced line must be to its replacement,
+	// as 1 - distance / max(length), for its character edits to be shown.
+	// Less similar lines are only removed and added.
+	MinSimilarity float64
+}
+
+func DefaultOptions() Options {
+	return Options{Context: 3, MaxCells: 4_000_000, MinSimilarity: 0.5}
+}
+
+// Line is a line of a hunk: ' ' for context, '-' for a removed line and '+'
+// for an added line.
+type Line struct {
+	Kind byte
+	Text string
+}
+
+// CharEdit holds the character edits of a replaced line, rendered by
+// render.Diff. Lines are 0-based like the hunk starts.
+type CharEdit struct {
+	SourceLine int
+	TargetLine int
+	Text       string
+}
+
+type Hunk struct {
+	SourceStart int
+	SourceLines int
+	TargetStart int
+	TargetLines int
+	Lines       []Line
+	CharEdits   []CharEdit
+}
+
+// SplitLines splits text into lines without their line breaks.
+func SplitLines(text string) []string {
+	if text == "" {
+		return nil
+	}
+	lines := strings.Split(strings.TrimSuffix(text, "\n"), "\n")
+	for k := range lines {
+		lines[k] = strings.TrimSuffix(lines[k], "\r")
+	}
+	return lines
+}
+
+// Compare aligns the lines with unit costs, so a changed line is a replace
+// rather than a delete and an insert, and groups the edits into hunks with
+// opts.Context lines of context around them.
+func Compare(a, b []string, opts Options) []Hunk {
+	log := logger.Nop{}
+	costs := vagner_fisher.UnitCosts[string]{}
+
+	var script vagner_fisher.TokenScript[string]
+	if len(a)*len(b) > opts.MaxCells {
+		_, script = vagner_fisher.FindTokenEditScriptHirschberg(a, b, costs, log)
+	} else {
+		_, script = vagner_fisher.Distance(a, b, costs)
+	}
+
+	var hunks []Hunk
+	for lo := 0; lo < len(script); {
+		if script[lo].Op == vagner_fisher.Match {
+			lo++
+			continue
+		}
+
+		start, hi := max(0, lo-opts.Context), lo
+		for {
+			for hi < len(script) && script[hi].Op != vagner_fisher.Match {
+				hi++
+			}
+			next := hi
+			for next < len(script) && script[next].Op == vagner_fisher.Match {
+				next++
+			}
+			if next == len(script) || next-hi > 2*opts.Context {
+				break
+			}
+			hi = next
+		}
+		end := min(len(script), hi+opts.Context)
+
+		hunks = append(hunks, buildHunk(script[start:end], opts, log))
+		lo = end
+	}
+
+	return hunks
+}
+
+func buildHunk(script vagner_fisher.TokenScript[string], opts Options, log vagner_fisher.Logger) Hunk {
+	hunk := Hunk{SourceStart: script[0].SourceIndex, TargetStart: script[0].TargetIndex}
+
+	var removed, added []vagner_fisher.TokenEdit[string]
+	flush := func() {
+		for _, e := range removed {
+			hunk.Lines = append(hunk.Lines, Line{'-', e.From})
+		}
+		for _, e := range added {
+			hunk.Lines = append(hunk.Lines, Line{'+', e.To})
+		}
+		if opts.CharEdits {
+			hunk.CharEdits = append(hunk.CharEdits, charEdits(removed, added, opts.MinSimilarity, log)...)
+		}
+		removed, added = nil, nil
+	}
+
+	for _, e := range script {
+		switch e.Op {
+		case vagner_fisher.Match:
+			flush()
+			hunk.Lines = append(hunk.Lines, Line{' ', e.From})
+			hunk.SourceLines++
+			hunk.TargetLines++
+		case vagner_fisher.Delete:
+			removed = append(removed, e)
+			hunk.SourceLines++
+		case vagner_fisher.Insert:
+			added = append(added, e)
+			hunk.TargetLines++
+		case vagner_fisher.Replace:
+			removed = append(removed, vagner_fisher.TokenEdit[string]{Op: vagner_fisher.Delete, SourceIndex: e.SourceIndex, From: e.From})
+			added = append(added, vagner_fisher.TokenEdit[string]{Op: vagner_fisher.Insert, TargetIndex: e.TargetIndex, To: e.To})
+			hunk.SourceLines++
+			hunk.TargetLines++
+		}
+	}
+	flush()
+
+	return hunk
+}
+
+// maxPairedLines bounds the removed times added lines of a change that
+// charEdits pairs up, as every pair costs a character alignment.
+const maxPairedLines = 10_000
+
+// similarLines prices lines for pairing the removed and added lines of a
+// change. Replacing a line by one less similar than minSimilarity costs more
+// than removing it and adding the other, so such lines are never paired.
+type similarLines struct {
+	minSimilarity float64
+}
+
+func (c similarLines) ReplaceCost(a, b string) int {
+	distance := vagner_fisher.FindLevenshteinDistanceLinear(a, b, vagner_fisher.Runes, vagner_fisher.UnitCosts[string]{}, nil)
+	length := max(utf8.RuneCountInString(a), utf8.RuneCountInString(b))
+	if 1-float64(distance)/float64(length) >= c.minSimilarity {
+		return 1
+	}
+	return 3
+}
+
+func (similarLines) InsertCost(b string) int { return 1 }
+func (similarLines) DeleteCost(a string) int { return 1 }
+
+// Unit keeps the DP from asking ReplaceCost about every pair of lines up front.
+func (similarLines) Unit() bool { return false }
+
+// charEdits pairs the removed lines of a change with the added lines they are
+// most similar to, in order, and diffs the characters of every pair.
+func charEdits(removed, added []vagner_fisher.TokenEdit[string], minSimilarity float64,
+	log vagner_fisher.Logger) []CharEdit {
+	if len(removed) == 0 || len(added) == 0 || len(removed)*len(added) > maxPairedLines {
+		return nil
+	}
+
+	from, to := make([]string, len(removed)), make([]string, len(added))
+	for k, e := range removed {
+		from[k] = e.From
+	}
+	for k, e := range added {
+		to[k] = e.To
+	}
+
+	var edits []CharEdit
+	_, pairs := vagner_fisher.Distance(from, to, similarLines{minSimilarity})
+	for _, pair := range pairs {
+		if pair.Op != vagner_fisher.Replace {
+			continue
+		}
+		_, chars := vagner_fisher.FindEditScript(pair.From, pair.To, vagner_fisher.Runes, vagner_fisher.NoTransposition,
+			vagner_fisher.UnitCosts[string]{}, log)
+		edits = append(edits, CharEdit{removed[pair.SourceIndex].SourceIndex, added[pair.TargetIndex].TargetIndex,
+			render.Diff(chars)})
+	}
+	return edits
+}
+
+// unifiedRange prints a hunk range the way diff -u does: the start is 1-based,
+// or the line before the hunk when it is empty, and a count of 1 is omitted.
+func unifiedRange(start, count int) string {
+	if count == 0 {
+		return fmt.Sprintf("%d,0", start)
+	}
+	if count == 1 {
+		return fmt.Sprintf("%d", start+1)
+	}
+	return fmt.Sprintf("%d,%d", start+1, count)
+}
+
+func WriteUnified(w io.Writer, sourceName, targetName string, hunks []Hunk) error {
+	if len(hunks) == 0 {
+		return nil
+	}
+
+	if _, err := fmt.Fprintf(w, "--- %s\n+++ %s\n", sourceName, targetName); err != nil {
+		return err
+	}
+	for _, hunk := range hunks {
+		_, err := fmt.Fprintf(w, "@@ -%s +%s @@\n", unifiedRange(hunk.SourceStart, hunk.SourceLines),
+			unifiedRange(hunk.TargetStart, hunk.TargetLines))
+		if err != nil {
+			return err
+		}
+		for _, line := range hunk.Lines {
+			if _, err := fmt.Fprintf(w, "%c%s\n", line.Kind, line.Text); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// WriteCharEdits lists the character edits of the hunks as "~-12+14 text",
+// with the 1-based line numbers of both files. It is kept apart from
+// WriteUnified so that the diff stays a valid patch.
+func WriteCharEdits(w io.Writer, hunks []Hunk) error {
+	for _, hunk := range hunks {
+		for _, edit := range hunk.CharEdits {
+			if _, err := fmt.Fprintf(w, "~-%d+%d %s\n", edit.SourceLine+1, edit.TargetLine+1, edit.Text); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
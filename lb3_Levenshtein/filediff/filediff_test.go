@@ -0,0 +1,132 @@
+package filediff
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func numberedLines(n int) []string {
+	lines := make([]string, n)
+	for k := range lines {
+		lines[k] = fmt.Sprintf("line %d", k+1)
+	}
+	return lines
+}
+
+// edited returns a copy of lines with the lines at the keys replaced, or
+// removed when the value is empty.
+func edited(lines []string, changes map[int]string) []string {
+	var result []string
+	for k, line := range lines {
+		if change, ok := changes[k]; !ok {
+			result = append(result, line)
+		} else if change != "" {
+			result = append(result, change)
+		}
+	}
+	return result
+}
+
+func writeLines(t *testing.T, path string, lines []string) {
+	t.Helper()
+	var text strings.Builder
+	for _, line := range lines {
+		text.WriteString(line + "\n")
+	}
+	if err := os.WriteFile(path, []byte(text.String()), 0o644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// The changes are unambiguous, so the hunks must be the ones diff -u prints.
+func TestUnifiedMatchesDiff(t *testing.T) {
+	diff, err := exec.LookPath("diff")
+	if err != nil {
+		t.Skip("diff is not installed")
+	}
+
+	lines := numberedLines(20)
+	tests := []struct {
+		name string
+		a, b []string
+	}{
+		{"identical", lines, lines},
+		{"replace", lines, edited(lines, map[int]string{9: "line ten"})},
+		{"two hunks", lines, edited(lines, map[int]string{1: "line two", 17: "line eighteen"})},
+		{"merged hunk", lines, edited(lines, map[int]string{4: "line five", 10: ""})},
+		{"insert at start", lines[:5], append([]string{"header"}, lines[:5]...)},
+		{"delete at end", lines[:6], lines[:4]},
+		{"empty source", nil, lines[:2]},
+		{"empty target", lines[:2], nil},
+	}
+
+	dir := t.TempDir()
+	source, target := filepath.Join(dir, "a"), filepath.Join(dir, "b")
+	for _, test := range tests {
+		writeLines(t, source, test.a)
+		writeLines(t, target, test.b)
+
+		want, err := exec.Command(diff, "-u", "--label", "a", "--label", "b", source, target).Output()
+		var exit *exec.ExitError
+		if err != nil && !(errors.As(err, &exit) && exit.ExitCode() == 1) {
+			t.Fatalf("%s: diff: %v", test.name, err)
+		}
+
+		var got bytes.Buffer
+		if err := WriteUnified(&got, "a", "b", Compare(test.a, test.b, DefaultOptions())); err != nil {
+			t.Fatal(err)
+		}
+		if got.String() != string(want) {
+			t.Errorf("%s: got\n%s\nwant\n%s", test.name, got.String(), want)
+		}
+	}
+}
+
+func TestCharEdits(t *testing.T) {
+	a := []string{"package main", "func add(a, b int) int {", "\treturn a + b", "}"}
+	b := []string{"package main", "func sum(a, b int) int {", "\tpanic(\"unreachable\")", "}"}
+
+	opts := DefaultOptions()
+	opts.CharEdits = true
+	hunks := Compare(a, b, opts)
+	if len(hunks) != 1 {
+		t.Fatalf("Compare returned %d hunks, want 1", len(hunks))
+	}
+	if n := len(hunks[0].Lines); n != 6 {
+		t.Errorf("hunk has %d lines, want 6", n)
+	}
+
+	var out bytes.Buffer
+	if err := WriteCharEdits(&out, hunks); err != nil {
+		t.Fatal(err)
+	}
+	// Only the renamed function is similar enough to its replacement.
+	want := "~-2+2 func [-add-]{+sum+}(a, b int) int {\n"
+	if out.String() != want {
+		t.Errorf("WriteCharEdits = %q, want %q", out.String(), want)
+	}
+}
+
+func TestSplitLines(t *testing.T) {
+	tests := []struct {
+		text string
+		want []string
+	}{
+		{"", nil},
+		{"a", []string{"a"}},
+		{"a\n", []string{"a"}},
+		{"a\r\nb\n\n", []string{"a", "b", ""}},
+	}
+	for _, test := range tests {
+		if got := SplitLines(test.text); !slices.Equal(got, test.want) {
+			t.Errorf("SplitLines(%q) = %q, want %q", test.text, got, test.want)
+		}
+	}
+}
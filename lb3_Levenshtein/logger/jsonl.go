@@ -0,0 +1,62 @@
+package logger
+
+import (
+	"encoding/json"
+	"io"
+	"sync"
+)
+
+// JSONLines writes every message and matrix as one JSON object per line:
+//
+//	{"title":"Init","message":"..."}
+//	{"title":"Final DP","matrix":[[0,1],[1,0]]}
+//
+// Rune matrices are written as one string per row. It is safe for concurrent
+// use; the first write error is kept and returned by Err.
+type JSONLines struct {
+	mu      sync.Mutex
+	encoder *json.Encoder
+	err     error
+}
+
+type jsonLine struct {
+	Title   string `json:"title"`
+	Message string `json:"message,omitempty"`
+	Matrix  any    `json:"matrix,omitempty"`
+}
+
+func NewJSONLines(w io.Writer) *JSONLines {
+	encoder := json.NewEncoder(w)
+	encoder.SetEscapeHTML(false)
+	return &JSONLines{encoder: encoder}
+}
+
+func (j *JSONLines) write(line jsonLine) {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	if j.err == nil {
+		j.err = j.encoder.Encode(line)
+	}
+}
+
+func (j *JSONLines) LogMsg(title, message, color string) {
+	j.write(jsonLine{Title: title, Message: message})
+}
+
+func (j *JSONLines) LogRuneMatrix(title string, data [][]rune, color string) {
+	j.write(jsonLine{Title: title, Matrix: runeRows(data)})
+}
+
+func (j *JSONLines) LogCostMatrix(title string, data [][]int, color string) {
+	j.write(jsonLine{Title: title, Matrix: data})
+}
+
+func (j *JSONLines) Enabled() bool {
+	return true
+}
+
+func (j *JSONLines) Err() error {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	return j.err
+}
@@ -16,6 +16,7 @@ const (
 	ColorWhite  = "\033[37m"
 )
 
+// Logger writes debug output when Debug is set. A nil *Logger logs nothing.
 type Logger struct {
 	Writer *bufio.Writer
 	Debug  bool
@@ -32,15 +33,19 @@ func (l *Logger) SetDebugMode() {
 	l.Debug = true
 }
 
+func (l *Logger) Enabled() bool {
+	return l != nil && l.Debug
+}
+
 func (l *Logger) LogMsg(title, message, color string) {
-	if l.Debug {
+	if l != nil && l.Debug {
 		fmt.Fprintf(l.Writer, "%s[%s]:%s %s\n", color, title, ColorReset, message)
 		l.Writer.Flush()
 	}
 }
 
 func (l *Logger) LogRuneMatrix(title string, data [][]rune, color string) {
-	if l.Debug {
+	if l != nil && l.Debug {
 		fmt.Fprintf(l.Writer, "%s[%s]:%s \n", color, title, ColorReset)
 		for _, row := range data {
 			for _, val := range row {
@@ -53,7 +58,7 @@ func (l *Logger) LogRuneMatrix(title string, data [][]rune, color string) {
 }
 
 func (l *Logger) LogCostMatrix(title string, data [][]int, color string) {
-	if l.Debug {
+	if l != nil && l.Debug {
 		fmt.Fprintf(l.Writer, "%s[%s]:%s \n", color, title, ColorReset)
 		for _, row := range data {
 			for _, val := range row {
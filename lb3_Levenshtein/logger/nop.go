@@ -0,0 +1,9 @@
+package logger
+
+// Nop discards everything, so the algorithms skip their debug-only work.
+type Nop struct{}
+
+func (Nop) LogMsg(title, message, color string)                     {}
+func (Nop) LogRuneMatrix(title string, data [][]rune, color string) {}
+func (Nop) LogCostMatrix(title string, data [][]int, color string)  {}
+func (Nop) Enabled() bool                                           { return false }
@@ -0,0 +1,41 @@
+package logger
+
+import (
+	"context"
+	"log/slog"
+)
+
+// Slog forwards messages to a slog.Logger at debug level without colours.
+// The title becomes the "title" attribute of a message and the message of a
+// matrix, whose rows are logged in the "matrix" attribute.
+type Slog struct {
+	logger *slog.Logger
+}
+
+func NewSlog(logger *slog.Logger) *Slog {
+	return &Slog{logger: logger}
+}
+
+func (s *Slog) LogMsg(title, message, color string) {
+	s.logger.Debug(message, slog.String("title", title))
+}
+
+func (s *Slog) LogRuneMatrix(title string, data [][]rune, color string) {
+	s.logger.Debug(title, slog.Any("matrix", runeRows(data)))
+}
+
+func (s *Slog) LogCostMatrix(title string, data [][]int, color string) {
+	s.logger.Debug(title, slog.Any("matrix", data))
+}
+
+func (s *Slog) Enabled() bool {
+	return s.logger.Enabled(context.Background(), slog.LevelDebug)
+}
+
+func runeRows(data [][]rune) []string {
+	rows := make([]string, len(data))
+	for i, row := range data {
+		rows[i] = string(row)
+	}
+	return rows
+}
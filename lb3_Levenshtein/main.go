@@ -4,63 +4,671 @@ import (
 	"bufio"
 	"flag"
 	"fmt"
+	"io"
+	"math/rand"
 	"os"
+	"slices"
 	"strconv"
 	"strings"
+	"time"
+	"unicode/utf8"
 
+	"lb3_Levenshtein/filediff"
 	"lb3_Levenshtein/logger"
+	"lb3_Levenshtein/pairwise"
+	"lb3_Levenshtein/render"
+	"lb3_Levenshtein/suggest"
 	"lb3_Levenshtein/vagner_fisher"
 )
 
-func readInputStrings(reader *bufio.Reader, writer *bufio.Writer) (string, string) {
-	fmt.Fprint(writer, "Enter the first string: ")
-	writer.Flush()
-	s1, _ := reader.ReadString('\n')
-	s1 = strings.TrimSpace(s1)
+// Exit codes: a pair over the -max bound or files that differ in the diff
+// command exit with exitMismatch, bad usage, input and I/O with exitError.
+const (
+	exitOK       = 0
+	exitMismatch = 1
+	exitError    = 2
+)
 
-	fmt.Fprint(writer, "Enter the second string: ")
-	writer.Flush()
-	s2, _ := reader.ReadString('\n')
-	s2 = strings.TrimSpace(s2)
+// isTerminal reports whether f is a character device, so prompts are only
+// printed when someone is typing the answers.
+func isTerminal(f *os.File) bool {
+	info, err := f.Stat()
+	return err == nil && info.Mode()&os.ModeCharDevice != 0
+}
 
-	return s1, s2
+func readLine(reader *bufio.Reader, prompt io.Writer, msg string) (string, error) {
+	fmt.Fprint(prompt, msg)
+	if w, ok := prompt.(*bufio.Writer); ok {
+		w.Flush()
+	}
+	line, err := reader.ReadString('\n')
+	if err == io.EOF && line != "" {
+		err = nil
+	}
+	return strings.TrimSpace(line), err
 }
 
-func readInputConfig(reader *bufio.Reader, writer *bufio.Writer) ([]string, []string, []string) {
-	fmt.Fprint(writer, "Enter the costs (replace, insert, delete): ")
-	writer.Flush()
-	costsInput, _ := reader.ReadString('\n')
-	costs := strings.Split(strings.TrimSpace(costsInput), " ")
-	if len(costs) != 3 {
-		fmt.Fprintln(os.Stderr, "Invalid input. Please enter 3 costs separated by spaces.")
-		os.Exit(1)
+func readInputStrings(reader *bufio.Reader, prompt io.Writer) (string, string, error) {
+	s1, err := readLine(reader, prompt, "Enter the first string: ")
+	if err != nil {
+		return "", "", fmt.Errorf("reading the first string: %w", err)
+	}
+	s2, err := readLine(reader, prompt, "Enter the second string: ")
+	if err != nil {
+		return "", "", fmt.Errorf("reading the second string: %w", err)
 	}
+	return s1, s2, nil
+}
 
-	fmt.Fprint(writer, "Enter special runes (replace, insert): ")
-	writer.Flush()
-	specialRunesInput, _ := reader.ReadString('\n')
-	specialRunesStrs := strings.Split(strings.TrimSpace(specialRunesInput), " ")
+func readInputConfig(reader *bufio.Reader, prompt io.Writer, withTranspose bool) ([]string, []string, []string, error) {
+	expectedCosts := 3
+	msg := "Enter the costs (replace, insert, delete): "
+	if withTranspose {
+		expectedCosts = 4
+		msg = "Enter the costs (replace, insert, delete, transpose): "
+	}
+	costsInput, err := readLine(reader, prompt, msg)
+	if err != nil {
+		return nil, nil, nil, fmt.Errorf("reading the costs: %w", err)
+	}
+	costs := strings.Fields(costsInput)
+	if len(costs) != expectedCosts {
+		return nil, nil, nil, fmt.Errorf("invalid input. Please enter %d costs separated by spaces", expectedCosts)
+	}
+
+	specialRunesInput, err := readLine(reader, prompt, "Enter special runes (replace, insert): ")
+	if err != nil {
+		return nil, nil, nil, fmt.Errorf("reading the special runes: %w", err)
+	}
+	specialRunesStrs := strings.Fields(specialRunesInput)
 	if len(specialRunesStrs) != 2 {
-		fmt.Fprintln(os.Stderr, "Invalid input. Please enter 2 special runes separated by spaces.")
-		os.Exit(1)
+		return nil, nil, nil, fmt.Errorf("invalid input. Please enter 2 special runes separated by spaces")
 	}
 
-	fmt.Fprint(writer, "Enter special runes costs (replace, insert): ")
-	writer.Flush()
-	specialRunesCostsInput, _ := reader.ReadString('\n')
-	specialRunesCosts := strings.Split(strings.TrimSpace(specialRunesCostsInput), " ")
+	specialRunesCostsInput, err := readLine(reader, prompt, "Enter special runes costs (replace, insert): ")
+	if err != nil {
+		return nil, nil, nil, fmt.Errorf("reading the special runes costs: %w", err)
+	}
+	specialRunesCosts := strings.Fields(specialRunesCostsInput)
 	if len(specialRunesCosts) != 2 {
-		fmt.Fprintln(os.Stderr, "Invalid input. Please enter 2 special runes costs separated by spaces.")
-		os.Exit(1)
+		return nil, nil, nil, fmt.Errorf("invalid input. Please enter 2 special runes costs separated by spaces")
+	}
+
+	return costs, specialRunesStrs, specialRunesCosts, nil
+}
+
+func parseCostModel(costs, specialRunesStrs, specialRunesCosts []string) (vagner_fisher.CostModel, error) {
+	var values []int
+	for _, costStr := range append(slices.Clone(costs), specialRunesCosts...) {
+		cost, err := strconv.Atoi(costStr)
+		if err != nil {
+			return nil, fmt.Errorf("parsing cost: %w", err)
+		}
+		values = append(values, cost)
+	}
+
+	var runes []rune
+	for _, runeStr := range specialRunesStrs {
+		if utf8.RuneCountInString(runeStr) != 1 {
+			return nil, fmt.Errorf("invalid rune input %q. Please enter a single character", runeStr)
+		}
+		r, _ := utf8.DecodeRuneInString(runeStr)
+		runes = append(runes, r)
+	}
+
+	specialRunes := vagner_fisher.SpecialRunes{
+		Replace: runes[0],
+		Insert:  runes[1],
+	}
+
+	opCosts := vagner_fisher.OperationCosts{
+		Replace:        values[0],
+		Insert:         values[1],
+		Delete:         values[2],
+		SpecialReplace: values[len(costs)],
+		SpecialInsert:  values[len(costs)+1],
+	}
+	if len(costs) == 4 {
+		opCosts.Transpose = &values[3]
+	}
+
+	return vagner_fisher.NewClassicCosts(&opCosts, &specialRunes), nil
+}
+
+// flagCostModel builds the cost model of the -op-costs, -special and
+// -special-costs flags. Missing costs default to 1 and missing special runes to
+// NUL, which never occurs in the input.
+func flagCostModel(opCosts, special, specialCosts string, withTranspose bool) (vagner_fisher.CostModel, error) {
+	expectedCosts := 3
+	if withTranspose {
+		expectedCosts = 4
+	}
+
+	costs := strings.Fields(opCosts)
+	if opCosts == "" {
+		costs = strings.Fields(strings.Repeat("1 ", expectedCosts))
+	}
+	if len(costs) != expectedCosts {
+		return nil, fmt.Errorf("expected %d costs in -op-costs, got %d", expectedCosts, len(costs))
+	}
+
+	specialRunesStrs := []string{"\x00", "\x00"}
+	if special != "" {
+		specialRunesStrs = strings.Fields(special)
+	}
+	specialRunesCosts := costs[:2]
+	if specialCosts != "" {
+		specialRunesCosts = strings.Fields(specialCosts)
+	}
+	if len(specialRunesStrs) != 2 || len(specialRunesCosts) != 2 {
+		return nil, fmt.Errorf("expected 2 special runes and 2 special rune costs")
+	}
+
+	return parseCostModel(costs, specialRunesStrs, specialRunesCosts)
+}
+
+// loadCosts loads the cost table of the -costs flag, or builds the model of
+// the -op-costs, -special and -special-costs flags when there is none.
+func loadCosts(costsFile, opCosts, special, specialCosts string, withTranspose bool) (vagner_fisher.CostModel, error) {
+	if costsFile == "" {
+		return flagCostModel(opCosts, special, specialCosts, withTranspose)
+	}
+	table, err := vagner_fisher.LoadCostTable(costsFile)
+	if err != nil {
+		return nil, fmt.Errorf("loading cost table: %w", err)
+	}
+	return table, nil
+}
+
+func writeTrace(path string, trace *vagner_fisher.Trace) error {
+	file, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	if err := trace.WriteJSON(file); err != nil {
+		file.Close()
+		return err
+	}
+	return file.Close()
+}
+
+func printOptimalScripts(writer *bufio.Writer, optimal *vagner_fisher.OptimalScripts, all, samples int) {
+	fmt.Fprintln(writer, "Optimal scripts: "+optimal.Count().String())
+
+	shown := 0
+	for script := range optimal.All() {
+		if shown == all {
+			break
+		}
+		fmt.Fprintln(writer, "  "+script)
+		shown++
 	}
 
-	return costs, specialRunesStrs, specialRunesCosts
+	r := rand.New(rand.NewSource(time.Now().UnixNano()))
+	for range samples {
+		fmt.Fprintln(writer, "Sample: "+optimal.Sample(r))
+	}
+}
+
+func printScript(writer *bufio.Writer, script vagner_fisher.EditScript, format string, width int, showEdits bool) {
+	switch format {
+	case "align":
+		fmt.Fprintln(writer, "Alignment:\n"+render.Align(script))
+	case "wrap":
+		fmt.Fprintln(writer, "Alignment:\n"+render.Wrap(script, width))
+	case "diff":
+		fmt.Fprintln(writer, "Diff: "+render.Diff(script))
+	default:
+		fmt.Fprintln(writer, "Operations sequence: "+script.String())
+	}
+
+	if showEdits {
+		fmt.Fprintln(writer, "Edit script:")
+		for _, edit := range script {
+			fmt.Fprintln(writer, "  "+edit.String())
+		}
+	}
+}
+
+func runSearch(args []string) {
+	fs := flag.NewFlagSet("search", flag.ExitOnError)
+	k := fs.Int("k", 1, "Report matches with at most this many edits.")
+	costsFile := fs.String("costs", "", "Load a cost table from this file instead of using unit costs.")
+	graphemes := fs.Bool("graphemes", false, "Match extended grapheme clusters instead of runes.")
+	debugMode := fs.Bool("debug", false, "Enable debug mode.")
+	fs.Usage = func() {
+		fmt.Fprintln(os.Stderr, "Usage: search [-k N] [-costs file] [-graphemes] [-debug] pattern [file]")
+		fs.PrintDefaults()
+	}
+	fs.Parse(args)
+
+	if fs.NArg() < 1 || fs.NArg() > 2 {
+		fs.Usage()
+		os.Exit(exitError)
+	}
+
+	costs, err := loadCosts(*costsFile, "", "", "", false)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "Invalid costs:", err)
+		os.Exit(exitError)
+	}
+
+	input := os.Stdin
+	if fs.NArg() == 2 {
+		file, err := os.Open(fs.Arg(1))
+		if err != nil {
+			fmt.Fprintln(os.Stderr, "Error opening text:", err)
+			os.Exit(exitError)
+		}
+		defer file.Close()
+		input = file
+	}
+
+	writer := bufio.NewWriter(os.Stdout)
+	defer writer.Flush()
+
+	log := logger.NewLogger(writer)
+	if *debugMode {
+		log.SetDebugMode()
+	}
+
+	seg := vagner_fisher.Runes
+	if *graphemes {
+		seg = vagner_fisher.Graphemes
+	}
+
+	matcher := vagner_fisher.NewApproximateMatcher(fs.Arg(0), seg, *k, costs, log)
+	for match, err := range matcher.Matches(bufio.NewReader(input)) {
+		if err != nil {
+			writer.Flush()
+			fmt.Fprintln(os.Stderr, "Error reading text:", err)
+			os.Exit(exitError)
+		}
+		fmt.Fprintf(writer, "%d\t%d\t%d\n", match.Start, match.End, match.Cost)
+	}
+}
+
+func runMatrix(args []string) {
+	fs := flag.NewFlagSet("matrix", flag.ExitOnError)
+	workers := fs.Int("workers", 0, "Number of worker goroutines (defaults to the number of CPUs).")
+	triangle := fs.Bool("triangle", false, "Only compute the upper triangle of the matrix.")
+	format := fs.String("format", "csv", "Output format: 'csv' or 'json'.")
+	costsFile := fs.String("costs", "", "Load a cost table from this file instead of using unit costs.")
+	progress := fs.Bool("progress", false, "Report progress on stderr.")
+	fs.Usage = func() {
+		fmt.Fprintln(os.Stderr, "Usage: matrix [-workers N] [-triangle] [-format csv|json] [-costs file] [-progress] file")
+		fs.PrintDefaults()
+	}
+	fs.Parse(args)
+
+	if fs.NArg() != 1 || (*format != "csv" && *format != "json") {
+		fs.Usage()
+		os.Exit(exitError)
+	}
+
+	costs, err := loadCosts(*costsFile, "", "", "", false)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "Invalid costs:", err)
+		os.Exit(exitError)
+	}
+
+	data, err := os.ReadFile(fs.Arg(0))
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "Error reading strings:", err)
+		os.Exit(exitError)
+	}
+	strs := strings.Split(strings.TrimRight(string(data), "\n"), "\n")
+	for k := range strs {
+		strs[k] = strings.TrimSuffix(strs[k], "\r")
+	}
+
+	// A cost table may price inserts and deletes differently, so its distances
+	// are not mirrored.
+	opts := pairwise.Options{
+		Workers:    *workers,
+		Triangle:   *triangle,
+		Asymmetric: *costsFile != "",
+		Distance:   vagner_fisher.LevenshteinDistanceFunc(costs),
+	}
+	if *progress {
+		opts.Progress = func(rows, total int) {
+			fmt.Fprintf(os.Stderr, "\rRows: %d/%d", rows, total)
+			if rows == total {
+				fmt.Fprintln(os.Stderr)
+			}
+		}
+	}
+
+	writer := bufio.NewWriter(os.Stdout)
+	defer writer.Flush()
+
+	write := pairwise.WriteCSV
+	if *format == "json" {
+		write = pairwise.WriteJSON
+	}
+	if err := write(writer, strs, opts); err != nil {
+		writer.Flush()
+		fmt.Fprintln(os.Stderr, "Error writing matrix:", err)
+		os.Exit(exitError)
+	}
+}
+
+func runSuggest(args []string) {
+	fs := flag.NewFlagSet("suggest", flag.ExitOnError)
+	dictFile := fs.String("dict", "", "Dictionary file: one word per line, optionally followed by its frequency.")
+	top := fs.Int("n", 5, "Number of suggestions per unknown word.")
+	maxDistance := fs.Int("max", 2, "Only suggest words within this distance.")
+	costsFile := fs.String("costs", "", "Load a cost table from this file.")
+	opCosts := fs.String("op-costs", "1 1 1", "Replace, insert and delete costs when no cost table is given.")
+	special := fs.String("special", "", "Special replace and insert runes for -op-costs.")
+	specialCosts := fs.String("special-costs", "", "Costs of the special runes (default the replace and insert costs).")
+	fs.Usage = func() {
+		fmt.Fprintln(os.Stderr, "Usage: suggest -dict file [-n N] [-max k] [-costs file | -op-costs 'r i d' [-special 'r i' -special-costs 'r i']] < text")
+		fs.PrintDefaults()
+	}
+	fs.Parse(args)
+
+	if *dictFile == "" || fs.NArg() != 0 {
+		fs.Usage()
+		os.Exit(exitError)
+	}
+
+	costs, err := loadCosts(*costsFile, *opCosts, *special, *specialCosts, false)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "Invalid costs:", err)
+		os.Exit(exitError)
+	}
+
+	dict, err := suggest.LoadDictionary(*dictFile)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "Error loading dictionary:", err)
+		os.Exit(exitError)
+	}
+
+	writer := bufio.NewWriter(os.Stdout)
+	defer writer.Flush()
+
+	suggester := suggest.NewSuggester(dict, costs, *maxDistance)
+	seen := make(map[string]bool)
+	scanner := bufio.NewScanner(os.Stdin)
+	for scanner.Scan() {
+		for _, word := range suggest.Words(scanner.Text()) {
+			if dict.Contains(word) || seen[strings.ToLower(word)] {
+				continue
+			}
+			seen[strings.ToLower(word)] = true
+
+			suggestions := suggester.Suggest(word, *top)
+			if len(suggestions) == 0 {
+				fmt.Fprintf(writer, "%s: no suggestions\n", word)
+				continue
+			}
+			corrections := make([]string, len(suggestions))
+			for k, s := range suggestions {
+				corrections[k] = fmt.Sprintf("%s (%d)", s.Word, s.Distance)
+			}
+			fmt.Fprintf(writer, "%s: %s\n", word, strings.Join(corrections, ", "))
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		writer.Flush()
+		fmt.Fprintln(os.Stderr, "Error reading text:", err)
+		os.Exit(exitError)
+	}
+}
+
+func runDiff(args []string) {
+	fs := flag.NewFlagSet("diff", flag.ExitOnError)
+	opts := filediff.DefaultOptions()
+	fs.IntVar(&opts.Context, "context", opts.Context, "Number of unchanged lines shown around each change.")
+	fs.IntVar(&opts.MaxCells, "max-cells", opts.MaxCells, "Use the linear-memory alignment above this many DP cells.")
+	fs.BoolVar(&opts.CharEdits, "chars", false, "List the character edits of changed lines after the diff.")
+	fs.Float64Var(&opts.MinSimilarity, "similarity", opts.MinSimilarity,
+		"Only list the character edits of changed lines at least this similar (0 to 1).")
+	fs.Usage = func() {
+		fmt.Fprintln(os.Stderr, "Usage: diff [-context N] [-max-cells N] [-chars [-similarity s]] file1 file2")
+		fs.PrintDefaults()
+	}
+	fs.Parse(args)
+
+	if fs.NArg() != 2 || opts.Context < 0 {
+		fs.Usage()
+		os.Exit(exitError)
+	}
+
+	var lines [2][]string
+	for k, name := range fs.Args() {
+		data, err := os.ReadFile(name)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, "Error reading file:", err)
+			os.Exit(exitError)
+		}
+		lines[k] = filediff.SplitLines(string(data))
+	}
+
+	writer := bufio.NewWriter(os.Stdout)
+	hunks := filediff.Compare(lines[0], lines[1], opts)
+	err := filediff.WriteUnified(writer, fs.Arg(0), fs.Arg(1), hunks)
+	if err == nil && opts.CharEdits {
+		err = filediff.WriteCharEdits(writer, hunks)
+	}
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "Error writing diff:", err)
+		os.Exit(exitError)
+	}
+	writer.Flush()
+
+	if len(hunks) > 0 {
+		os.Exit(exitMismatch)
+	}
+}
+
+func runRender(args []string) {
+	fs := flag.NewFlagSet("render", flag.ExitOnError)
+	format := fs.String("format", "html", "Output format: 'html' page or 'svg' image.")
+	fs.Usage = func() {
+		fmt.Fprintln(os.Stderr, "Usage: render [-format html|svg] [trace.json]")
+		fmt.Fprintln(os.Stderr, "Draws the DP matrix of a trace recorded with -trace.")
+		fs.PrintDefaults()
+	}
+	fs.Parse(args)
+
+	if fs.NArg() > 1 || (*format != "html" && *format != "svg") {
+		fs.Usage()
+		os.Exit(exitError)
+	}
+
+	input := os.Stdin
+	if fs.NArg() == 1 {
+		file, err := os.Open(fs.Arg(0))
+		if err != nil {
+			fmt.Fprintln(os.Stderr, "Error opening trace:", err)
+			os.Exit(exitError)
+		}
+		defer file.Close()
+		input = file
+	}
+
+	trace, err := vagner_fisher.ReadTrace(input)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "Error loading trace:", err)
+		os.Exit(exitError)
+	}
+
+	writer := bufio.NewWriter(os.Stdout)
+	write := render.WriteMatrixHTML
+	if *format == "svg" {
+		write = render.WriteMatrixSVG
+	}
+	if err := write(writer, trace); err != nil {
+		fmt.Fprintln(os.Stderr, "Error rendering trace:", err)
+		os.Exit(exitError)
+	}
+	writer.Flush()
+}
+
+// alignmentModes are the values of the -mode flag.
+var alignmentModes = map[string]vagner_fisher.AlignmentMode{
+	"global": vagner_fisher.Global,
+	"prefix": vagner_fisher.Prefix,
+	"suffix": vagner_fisher.Suffix,
+	"infix":  vagner_fisher.Infix,
+	"local":  vagner_fisher.Local,
+}
+
+// transpositions are the values of the -transpose flag.
+var transpositions = map[string]vagner_fisher.Transposition{
+	"":     vagner_fisher.NoTransposition,
+	"osa":  vagner_fisher.OptimalStringAlignment,
+	"full": vagner_fisher.UnrestrictedTransposition,
+}
+
+// options holds the flags of a distance computation that depend on each other.
+type options struct {
+	algorithm     string
+	maxDistance   int
+	transposition string
+	allScripts    int
+	sampleScripts int
+	showScript    bool
+	format        string
+	mode          string
+	gapOpen       int
+	matrixName    string
+	traceFile     string
+	batchFile     string
+	debug         bool
+}
+
+// validateOptions rejects invalid flag values and flags that cannot be used
+// together, rather than ignoring some of them.
+func validateOptions(o options) error {
+	if o.format != "ops" && o.format != "align" && o.format != "wrap" && o.format != "diff" {
+		return fmt.Errorf("invalid format. Use 'ops', 'align', 'wrap' or 'diff'")
+	}
+	if o.algorithm != "full" && o.algorithm != "linear" && o.algorithm != "hirschberg" {
+		return fmt.Errorf("invalid algorithm. Use 'full', 'linear' or 'hirschberg'")
+	}
+	if _, ok := transpositions[o.transposition]; !ok {
+		return fmt.Errorf("invalid transposition mode. Use 'osa' or 'full'")
+	}
+	mode, ok := alignmentModes[o.mode]
+	if !ok {
+		return fmt.Errorf("invalid mode. Use 'global', 'prefix', 'suffix', 'infix' or 'local'")
+	}
+
+	enumerate := o.allScripts > 0 || o.sampleScripts > 0
+	switch {
+	case o.maxDistance >= 0 && (enumerate || o.showScript || o.format != "ops"):
+		return fmt.Errorf("-max only checks the distance and prints no scripts or alignments")
+	case (o.algorithm != "full" || o.maxDistance >= 0) && o.transposition != "":
+		return fmt.Errorf("transpositions require the 'full' algorithm")
+	case enumerate && (o.algorithm != "full" || o.transposition != ""):
+		return fmt.Errorf("enumerating optimal scripts requires the 'full' algorithm without transpositions")
+	case (o.showScript || o.format != "ops") && o.algorithm != "full":
+		return fmt.Errorf("printing the edit script or alignment requires the 'full' algorithm")
+	case mode != vagner_fisher.Global && (o.algorithm != "full" || o.maxDistance >= 0 || o.transposition != "" || enumerate):
+		return fmt.Errorf("alignment modes require the 'full' algorithm without transpositions or script enumeration")
+	case o.gapOpen >= 0 && (o.algorithm != "full" || o.maxDistance >= 0 || o.transposition != "" || enumerate ||
+		mode != vagner_fisher.Global):
+		return fmt.Errorf("affine gaps require the 'full' algorithm in global mode without transpositions or script enumeration")
+	case o.matrixName != "" && (o.algorithm != "full" || o.maxDistance >= 0 || o.transposition != "" || enumerate ||
+		mode != vagner_fisher.Global || o.gapOpen >= 0):
+		return fmt.Errorf("substitution matrices require the 'full' algorithm in global mode with linear gaps")
+	case o.traceFile != "" && (o.algorithm != "full" || o.maxDistance >= 0 || o.debug || o.batchFile != "" ||
+		o.matrixName != "" || mode != vagner_fisher.Global || o.gapOpen >= 0):
+		return fmt.Errorf("tracing requires the 'full' algorithm in global mode without debug output, affine gaps or batches")
+	case o.batchFile != "" && (o.matrixName != "" || mode != vagner_fisher.Global || enumerate || o.showScript ||
+		o.format != "ops"):
+		return fmt.Errorf("batch mode only computes global distances and operation sequences")
+	}
+	return nil
 }
 
 func main() {
+	if len(os.Args) > 1 {
+		switch os.Args[1] {
+		case "search":
+			runSearch(os.Args[2:])
+			return
+		case "matrix":
+			runMatrix(os.Args[2:])
+			return
+		case "suggest":
+			runSuggest(os.Args[2:])
+			return
+		case "diff":
+			runDiff(os.Args[2:])
+			return
+		case "render":
+			runRender(os.Args[2:])
+			return
+		}
+	}
+
 	debugMode := flag.Bool("debug", false, "Enable debug mode.")
+	graphemes := flag.Bool("graphemes", false, "Compare extended grapheme clusters instead of runes.")
+	transposition := flag.String("transpose", "", "Allow transpositions: 'osa' (restricted) or 'full' (unrestricted Damerau).")
+	algorithm := flag.String("algorithm", "full", "DP memory mode: 'full' matrices, 'linear' (distance only) or 'hirschberg'.")
+	maxDistance := flag.Int("max", -1, "Only check whether the distance is at most this value.")
+	costsFile := flag.String("costs", "", "Load a cost table from this file instead of prompting for costs.")
+	allScripts := flag.Int("all", 0, "Print up to this many optimal edit scripts.")
+	sampleScripts := flag.Int("sample", 0, "Print this many uniformly sampled optimal edit scripts.")
+	showScript := flag.Bool("script", false, "Print the edit script with positions, symbols and costs.")
+	format := flag.String("format", "ops", "Result format: 'ops' sequence, 'align' rows, 'wrap' aligned rows or inline 'diff'.")
+	width := flag.Int("width", 80, "Line width for the 'wrap' format.")
+	alignMode := flag.String("mode", "global", "Alignment mode: 'global', 'prefix', 'suffix', 'infix' or 'local'.")
+	matchScore := flag.Int("match", 1, "Score of a match in 'local' mode.")
+	gapOpen := flag.Int("gap-open", -1, "Use affine gaps with this opening cost instead of per-symbol insert and delete costs.")
+	gapExtend := flag.Int("gap-extend", 1, "Cost of every symbol of an affine gap.")
+	matrixName := flag.String("matrix", "", "Score a Needleman-Wunsch alignment with 'blosum62', 'pam250' or a matrix file.")
+	gapPenalty := flag.Int("gap", 4, "Penalty of every gap symbol in a Needleman-Wunsch alignment.")
+	opCosts := flag.String("op-costs", "", "Replace, insert, delete and, with -transpose, transpose costs instead of prompting (default all 1).")
+	special := flag.String("special", "", "Special replace and insert runes for -op-costs.")
+	specialCosts := flag.String("special-costs", "", "Costs of the special runes (default the replace and insert costs).")
+	traceFile := flag.String("trace", "", "Record every step of the DP to this JSON file for replay.")
+	batchFile := flag.String("batch", "", "Compute the distance of every pair in this file ('-' for stdin).")
+	batchFormat := flag.String("batch-format", "tsv", "Batch format: 'tsv' (two tab-separated strings per line) or 'jsonl' (objects with s1 and s2).")
+	flag.Usage = func() {
+		fmt.Fprintln(os.Stderr, "Usage: lb3 [flags] [s1 s2]")
+		fmt.Fprintln(os.Stderr, "       lb3 [flags] -batch file|- [-batch-format tsv|jsonl]")
+		fmt.Fprintln(os.Stderr, "       lb3 search|matrix|suggest|diff|render [flags] ...")
+		fmt.Fprintln(os.Stderr, "Without strings the costs and strings are read from stdin, with prompts if it is a terminal.")
+		fmt.Fprintln(os.Stderr, "Exit status is 0 on success, 1 if a distance exceeds -max and 2 on errors.")
+		flag.PrintDefaults()
+	}
 	flag.Parse()
 
+	if flag.NArg() != 0 && flag.NArg() != 2 {
+		flag.Usage()
+		os.Exit(exitError)
+	}
+	if *batchFile != "" && (flag.NArg() != 0 || *batchFormat != "tsv" && *batchFormat != "jsonl") {
+		flag.Usage()
+		os.Exit(exitError)
+	}
+
+	err := validateOptions(options{
+		algorithm:     *algorithm,
+		maxDistance:   *maxDistance,
+		transposition: *transposition,
+		allScripts:    *allScripts,
+		sampleScripts: *sampleScripts,
+		showScript:    *showScript,
+		format:        *format,
+		mode:          *alignMode,
+		gapOpen:       *gapOpen,
+		matrixName:    *matrixName,
+		traceFile:     *traceFile,
+		batchFile:     *batchFile,
+		debug:         *debugMode,
+	})
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "Invalid options:", err)
+		os.Exit(exitError)
+	}
+	mode, variant := alignmentModes[*alignMode], transpositions[*transposition]
+
 	if *debugMode {
 		fmt.Println("Debug mode enabled.")
 	}
@@ -69,47 +677,171 @@ func main() {
 	writer := bufio.NewWriter(os.Stdout)
 	defer writer.Flush()
 
-	costs, specialRunesStrs, specialRunesCosts := readInputConfig(reader, writer)
-	s1, s2 := readInputStrings(reader, writer)
+	interactive := *batchFile == "" && flag.NArg() == 0
+	var prompt io.Writer = writer
+	if !isTerminal(os.Stdin) {
+		prompt = io.Discard
+	}
 
-	parseCost := func(costStr string) int {
-		cost, err := strconv.Atoi(costStr)
+	var matrix *vagner_fisher.SubstitutionMatrix
+	var costs vagner_fisher.CostModel
+	switch {
+	case *matrixName == "blosum62":
+		matrix = vagner_fisher.BLOSUM62
+	case *matrixName == "pam250":
+		matrix = vagner_fisher.PAM250
+	case *matrixName != "":
+		matrix, err = vagner_fisher.LoadSubstitutionMatrix(*matrixName)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, "Error loading substitution matrix:", err)
+			os.Exit(exitError)
+		}
+	case *costsFile != "" || *opCosts != "" || *special != "" || *specialCosts != "" || !interactive:
+		costs, err = loadCosts(*costsFile, *opCosts, *special, *specialCosts, variant != vagner_fisher.NoTransposition)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, "Invalid costs:", err)
+			os.Exit(exitError)
+		}
+	default:
+		var costStrs, specialRunesStrs, specialRunesCosts []string
+		costStrs, specialRunesStrs, specialRunesCosts, err = readInputConfig(reader, prompt, variant != vagner_fisher.NoTransposition)
+		if err == nil {
+			costs, err = parseCostModel(costStrs, specialRunesStrs, specialRunesCosts)
+		}
+		if err != nil {
+			writer.Flush()
+			fmt.Fprintln(os.Stderr, "Invalid costs:", err)
+			os.Exit(exitError)
+		}
+	}
+
+	log := logger.NewLogger(writer)
+	if *debugMode {
+		log.SetDebugMode()
+	}
+
+	seg := vagner_fisher.Runes
+	if *graphemes {
+		seg = vagner_fisher.Graphemes
+	}
+
+	opts := pairOptions{
+		algorithm:   *algorithm,
+		maxDistance: *maxDistance,
+		seg:         seg,
+		variant:     variant,
+		costs:       costs,
+		log:         log,
+	}
+	if *gapOpen >= 0 {
+		opts.gaps = &vagner_fisher.AffineGaps{Open: *gapOpen, Extend: *gapExtend}
+	}
+
+	if *batchFile != "" {
+		input := os.Stdin
+		if *batchFile != "-" {
+			file, err := os.Open(*batchFile)
+			if err != nil {
+				fmt.Fprintln(os.Stderr, "Error opening batch:", err)
+				os.Exit(exitError)
+			}
+			defer file.Close()
+			input = file
+		}
+
+		exceeded, err := runBatch(input, writer, *batchFormat, opts)
+		writer.Flush()
 		if err != nil {
-			fmt.Fprintln(os.Stderr, "Error parsing cost:", err)
-			os.Exit(1)
+			fmt.Fprintln(os.Stderr, "Error in batch:", err)
+			os.Exit(exitError)
 		}
-		return cost
+		if exceeded {
+			os.Exit(exitMismatch)
+		}
+		return
 	}
 
-	parseRunes := func(runeStr string) rune {
-		if len(runeStr) != 1 {
-			fmt.Fprintln(os.Stderr, "Invalid rune input. Please enter a single character.")
-			os.Exit(1)
+	s1, s2 := flag.Arg(0), flag.Arg(1)
+	if interactive {
+		s1, s2, err = readInputStrings(reader, prompt)
+		if err != nil {
+			writer.Flush()
+			fmt.Fprintln(os.Stderr, "Invalid input:", err)
+			os.Exit(exitError)
 		}
-		return rune(runeStr[0])
+	}
+	if err := vagner_fisher.ValidateCosts(s1, s2, seg, variant, costs); err != nil {
+		writer.Flush()
+		fmt.Fprintln(os.Stderr, "Invalid costs:", err)
+		os.Exit(exitError)
 	}
 
-	specialRunes := vagner_fisher.SpecialRunes{
-		Replace: parseRunes(specialRunesStrs[0]),
-		Insert:  parseRunes(specialRunesStrs[1]),
+	if *maxDistance >= 0 {
+		result := computePair(s1, s2, opts)
+
+		fmt.Fprintln(writer, "\nResults:")
+		if !result.Exceeded {
+			fmt.Fprintln(writer, "Levenshtein distance: "+strconv.Itoa(result.Distance))
+			return
+		}
+		fmt.Fprintln(writer, "Levenshtein distance: > "+strconv.Itoa(*maxDistance))
+		writer.Flush()
+		os.Exit(exitMismatch)
 	}
 
-	opCosts := vagner_fisher.OperationCosts{
-		Replace:        parseCost(costs[0]),
-		Insert:         parseCost(costs[1]),
-		Delete:         parseCost(costs[2]),
-		SpecialReplace: parseCost(specialRunesCosts[0]),
-		SpecialInsert:  parseCost(specialRunesCosts[1]),
+	if matrix != nil {
+		score, script := vagner_fisher.FindNeedlemanWunschAlignment(s1, s2, seg, matrix, *gapPenalty, log)
+
+		fmt.Fprintln(writer, "\nResults:")
+		fmt.Fprintln(writer, "Alignment score: "+strconv.Itoa(score))
+		printScript(writer, script, *format, *width, *showScript)
+		return
 	}
 
-	log := logger.NewLogger(writer)
-	if *debugMode {
-		log.SetDebugMode()
+	if mode != vagner_fisher.Global {
+		var alignment vagner_fisher.Alignment
+		if mode == vagner_fisher.Local {
+			alignment = vagner_fisher.FindLocalAlignment(s1, s2, seg, *matchScore, costs, log)
+		} else {
+			alignment = vagner_fisher.FindAlignment(s1, s2, seg, mode, costs, log)
+		}
+		source, target := alignment.Substrings(s1, s2)
+
+		fmt.Fprintln(writer, "\nResults:")
+		if mode == vagner_fisher.Local {
+			fmt.Fprintln(writer, "Local alignment score: "+strconv.Itoa(alignment.Score))
+		}
+		fmt.Fprintln(writer, "Levenshtein distance: "+strconv.Itoa(alignment.Distance))
+		fmt.Fprintf(writer, "Aligned region: s1[%d:%d] %q, s2[%d:%d] %q\n", alignment.SourceStart, alignment.SourceEnd,
+			source, alignment.TargetStart, alignment.TargetEnd, target)
+		printScript(writer, alignment.Script, *format, *width, *showScript)
+		return
 	}
 
-	distance, operations := vagner_fisher.FindLevenshteinDistance(s1, s2, &opCosts, &specialRunes, log)
+	trace := &vagner_fisher.Trace{}
+	if *traceFile != "" {
+		opts.log = trace
+	}
+	result := computePair(s1, s2, opts)
+
+	if *traceFile != "" {
+		if err := writeTrace(*traceFile, trace); err != nil {
+			writer.Flush()
+			fmt.Fprintln(os.Stderr, "Error writing trace:", err)
+			os.Exit(exitError)
+		}
+	}
 
 	fmt.Fprintln(writer, "\nResults:")
-	fmt.Fprintln(writer, "Levenshtein distance: "+strconv.Itoa(distance))
-	fmt.Fprintln(writer, "Operations sequence: "+operations)
+	fmt.Fprintln(writer, "Levenshtein distance: "+strconv.Itoa(result.Distance))
+	switch *algorithm {
+	case "full":
+		printScript(writer, result.Script, *format, *width, *showScript)
+	case "hirschberg":
+		fmt.Fprintln(writer, "Operations sequence: "+result.Operations)
+	}
+
+	if *allScripts > 0 || *sampleScripts > 0 {
+		printOptimalScripts(writer, vagner_fisher.FindOptimalScripts(s1, s2, seg, costs, log), *allScripts, *sampleScripts)
+	}
 }
@@ -0,0 +1,131 @@
+package main
+
+import (
+	"bytes"
+	"errors"
+	"os"
+	"os/exec"
+	"strings"
+	"testing"
+
+	"lb3_Levenshtein/vagner_fisher"
+)
+
+// TestMain runs the command instead of the tests when runCommand sets
+// LB3_RUN_MAIN, so that tests can check its output and exit status.
+func TestMain(m *testing.M) {
+	if os.Getenv("LB3_RUN_MAIN") == "1" {
+		main()
+		os.Exit(exitOK)
+	}
+	os.Exit(m.Run())
+}
+
+// runCommand runs the test binary as the command with args and stdin, and
+// returns its output and exit status.
+func runCommand(t *testing.T, stdin string, args ...string) (string, string, int) {
+	t.Helper()
+	cmd := exec.Command(os.Args[0], args...)
+	cmd.Env = append(os.Environ(), "LB3_RUN_MAIN=1")
+	cmd.Stdin = strings.NewReader(stdin)
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout, cmd.Stderr = &stdout, &stderr
+
+	err := cmd.Run()
+	var exit *exec.ExitError
+	if errors.As(err, &exit) {
+		return stdout.String(), stderr.String(), exit.ExitCode()
+	}
+	if err != nil {
+		t.Fatal(err)
+	}
+	return stdout.String(), stderr.String(), exitOK
+}
+
+func TestCommandValidatesCosts(t *testing.T) {
+	tests := []struct {
+		name   string
+		stdin  string
+		args   []string
+		status int
+		output string
+	}{
+		{"negative cost", "", []string{"-op-costs", "-1 1 1", "abc", "bca"}, exitError, "negative cost"},
+		{"cheap unrestricted transposition", "", []string{"-transpose", "full", "-op-costs", "10 5 5 1", "abc", "bca"},
+			exitError, "transposition too cheap"},
+		{"exact unrestricted transposition", "", []string{"-transpose", "full", "-op-costs", "10 5 5 5", "abc", "bca"},
+			exitOK, "Levenshtein distance: 10"},
+		{"negative cost in batch", "a\tb\n", []string{"-batch", "-", "-op-costs", "1 1 -2"}, exitError,
+			"line 1: negative cost"},
+	}
+	for _, test := range tests {
+		stdout, stderr, status := runCommand(t, test.stdin, test.args...)
+		if status != test.status || !strings.Contains(stdout+stderr, test.output) {
+			t.Errorf("%s: exit status %d, output %q %q, want %d and %q", test.name, status, stdout, stderr,
+				test.status, test.output)
+		}
+	}
+}
+
+func TestFlagCostModelTranspose(t *testing.T) {
+	tests := []struct {
+		opCosts       string
+		withTranspose bool
+		want          int
+	}{
+		{"1 1 1 0", true, 0},
+		{"3 1 1 2", true, 2},
+		{"3 1 1", false, 3},
+		{"", true, 1},
+	}
+	for _, test := range tests {
+		costs, err := flagCostModel(test.opCosts, "", "", test.withTranspose)
+		if err != nil {
+			t.Fatalf("flagCostModel(%q): %v", test.opCosts, err)
+		}
+		if got := costs.(vagner_fisher.TranspositionCosts).TransposeCost("a", "b"); got != test.want {
+			t.Errorf("flagCostModel(%q): TransposeCost = %d, want %d", test.opCosts, got, test.want)
+		}
+	}
+}
+
+func TestValidateOptions(t *testing.T) {
+	defaults := options{algorithm: "full", maxDistance: -1, format: "ops", mode: "global", gapOpen: -1}
+	tests := []struct {
+		name  string
+		edit  func(o *options)
+		valid bool
+	}{
+		{"defaults", func(o *options) {}, true},
+		{"max", func(o *options) { o.maxDistance = 2 }, true},
+		{"max in batch", func(o *options) { o.maxDistance, o.batchFile = 2, "-" }, true},
+		{"linear", func(o *options) { o.algorithm = "linear" }, true},
+		{"transposed script", func(o *options) { o.transposition, o.showScript = "osa", true }, true},
+		{"unknown format", func(o *options) { o.format = "html" }, false},
+		{"unknown algorithm", func(o *options) { o.algorithm = "quadratic" }, false},
+		{"unknown transposition", func(o *options) { o.transposition = "damerau" }, false},
+		{"unknown mode", func(o *options) { o.mode = "semi" }, false},
+		{"max with all", func(o *options) { o.maxDistance, o.allScripts = 2, 3 }, false},
+		{"max with sample", func(o *options) { o.maxDistance, o.sampleScripts = 2, 1 }, false},
+		{"max with script", func(o *options) { o.maxDistance, o.showScript = 2, true }, false},
+		{"max with format", func(o *options) { o.maxDistance, o.format = 2, "align" }, false},
+		{"max with transpositions", func(o *options) { o.maxDistance, o.transposition = 2, "osa" }, false},
+		{"linear with script", func(o *options) { o.algorithm, o.showScript = "linear", true }, false},
+		{"hirschberg with format", func(o *options) { o.algorithm, o.format = "hirschberg", "diff" }, false},
+		{"hirschberg with transpositions", func(o *options) { o.algorithm, o.transposition = "hirschberg", "full" }, false},
+		{"all with transpositions", func(o *options) { o.allScripts, o.transposition = 1, "osa" }, false},
+		{"local with max", func(o *options) { o.mode, o.maxDistance = "local", 1 }, false},
+		{"affine in infix mode", func(o *options) { o.gapOpen, o.mode = 2, "infix" }, false},
+		{"matrix with affine gaps", func(o *options) { o.matrixName, o.gapOpen = "blosum62", 2 }, false},
+		{"trace with debug", func(o *options) { o.traceFile, o.debug = "t.json", true }, false},
+		{"trace in batch", func(o *options) { o.traceFile, o.batchFile = "t.json", "-" }, false},
+		{"batch with script", func(o *options) { o.batchFile, o.showScript = "-", true }, false},
+	}
+	for _, test := range tests {
+		o := defaults
+		test.edit(&o)
+		if err := validateOptions(o); (err == nil) != test.valid {
+			t.Errorf("%s: validateOptions = %v, want valid %t", test.name, err, test.valid)
+		}
+	}
+}
@@ -0,0 +1,127 @@
+package metrics
+
+import (
+	"lb3_Levenshtein/logger"
+	"lb3_Levenshtein/vagner_fisher"
+)
+
+// Hamming counts the positions where the strings differ. Symbols past the end
+// of the shorter string count as differences.
+type Hamming struct{}
+
+func (Hamming) Name() string {
+	return "hamming"
+}
+
+func (Hamming) Distance(s1, s2 string) float64 {
+	a, b := symbols(s1), symbols(s2)
+	if len(a) > len(b) {
+		a, b = b, a
+	}
+
+	distance := len(b) - len(a)
+	for k := range a {
+		if a[k] != b[k] {
+			distance++
+		}
+	}
+	return float64(distance)
+}
+
+func (h Hamming) Similarity(s1, s2 string) float64 {
+	return ratio(h.Distance(s1, s2), float64(max(len(symbols(s1)), len(symbols(s2)))))
+}
+
+// LCS is the insert/delete distance n + m - 2*lcs, where lcs is the length of
+// the longest common subsequence.
+type LCS struct{}
+
+func (LCS) Name() string {
+	return "lcs"
+}
+
+func longestCommonSubsequence(a, b []string) int {
+	prev := make([]int, len(b)+1)
+	cur := make([]int, len(b)+1)
+	for i := 1; i <= len(a); i++ {
+		for j := 1; j <= len(b); j++ {
+			if a[i-1] == b[j-1] {
+				cur[j] = prev[j-1] + 1
+			} else {
+				cur[j] = max(prev[j], cur[j-1])
+			}
+		}
+		prev, cur = cur, prev
+	}
+	return prev[len(b)]
+}
+
+func (LCS) Distance(s1, s2 string) float64 {
+	a, b := symbols(s1), symbols(s2)
+	return float64(len(a) + len(b) - 2*longestCommonSubsequence(a, b))
+}
+
+func (l LCS) Similarity(s1, s2 string) float64 {
+	return ratio(l.Distance(s1, s2), float64(len(symbols(s1))+len(symbols(s2))))
+}
+
+// Levenshtein wraps FindLevenshteinDistance. Similarity divides the distance
+// by the cost of the worst case for strings of these lengths: the cheaper of
+// replacing every position as if nothing matched and inserting or deleting
+// the rest, or deleting all of s1 and inserting all of s2. With unit costs
+// that bound is max(n, m). The zero value uses unit costs.
+type Levenshtein struct {
+	Costs vagner_fisher.CostModel
+	log   vagner_fisher.Logger
+}
+
+func NewLevenshtein(costs vagner_fisher.CostModel) *Levenshtein {
+	if costs == nil {
+		costs = vagner_fisher.NewCostTable(1, 1, 1, 1)
+	}
+	return &Levenshtein{
+		Costs: costs,
+		log:   logger.Nop{},
+	}
+}
+
+func (l *Levenshtein) Name() string {
+	return "levenshtein"
+}
+
+func (l *Levenshtein) costs() vagner_fisher.CostModel {
+	if l.Costs == nil {
+		return vagner_fisher.UnitCosts[string]{}
+	}
+	return l.Costs
+}
+
+func (l *Levenshtein) Distance(s1, s2 string) float64 {
+	distance, _ := vagner_fisher.FindLevenshteinDistance(s1, s2, l.costs(), l.log)
+	return float64(distance)
+}
+
+func (l *Levenshtein) Similarity(s1, s2 string) float64 {
+	a, b := symbols(s1), symbols(s2)
+	costs := l.costs()
+
+	replaceAll, rebuild := 0, 0
+	for k := range max(len(a), len(b)) {
+		switch {
+		case k >= len(a):
+			replaceAll += costs.InsertCost(b[k])
+		case k >= len(b):
+			replaceAll += costs.DeleteCost(a[k])
+		default:
+			replaceAll += costs.ReplaceCost(a[k], b[k])
+		}
+	}
+	for _, symbol := range a {
+		rebuild += costs.DeleteCost(symbol)
+	}
+	for _, symbol := range b {
+		rebuild += costs.InsertCost(symbol)
+	}
+
+	return ratio(l.Distance(s1, s2), float64(min(replaceAll, rebuild)))
+}
@@ -0,0 +1,94 @@
+package metrics
+
+// Jaro counts symbols that match within half the longer length of each other
+// and the transpositions among them.
+type Jaro struct{}
+
+func (Jaro) Name() string {
+	return "jaro"
+}
+
+func jaroSimilarity(a, b []string) float64 {
+	if len(a) == 0 && len(b) == 0 {
+		return 1
+	}
+	if len(a) == 0 || len(b) == 0 {
+		return 0
+	}
+
+	window := max(0, max(len(a), len(b))/2-1)
+	matchedA := make([]bool, len(a))
+	matchedB := make([]bool, len(b))
+
+	matches := 0
+	for i := range a {
+		for j := max(0, i-window); j < min(len(b), i+window+1); j++ {
+			if !matchedB[j] && a[i] == b[j] {
+				matchedA[i], matchedB[j] = true, true
+				matches++
+				break
+			}
+		}
+	}
+	if matches == 0 {
+		return 0
+	}
+
+	transpositions, j := 0, 0
+	for i := range a {
+		if !matchedA[i] {
+			continue
+		}
+		for !matchedB[j] {
+			j++
+		}
+		if a[i] != b[j] {
+			transpositions++
+		}
+		j++
+	}
+
+	m := float64(matches)
+	return (m/float64(len(a)) + m/float64(len(b)) + (m-float64(transpositions/2))/m) / 3
+}
+
+func (Jaro) Similarity(s1, s2 string) float64 {
+	return jaroSimilarity(symbols(s1), symbols(s2))
+}
+
+func (j Jaro) Distance(s1, s2 string) float64 {
+	return 1 - j.Similarity(s1, s2)
+}
+
+// JaroWinkler raises the Jaro similarity of strings sharing a prefix of up to
+// four symbols, once it exceeds BoostThreshold.
+type JaroWinkler struct {
+	PrefixScale    float64
+	BoostThreshold float64
+}
+
+func NewJaroWinkler() *JaroWinkler {
+	return &JaroWinkler{PrefixScale: 0.1, BoostThreshold: 0.7}
+}
+
+func (jw *JaroWinkler) Name() string {
+	return "jaro-winkler"
+}
+
+func (jw *JaroWinkler) Similarity(s1, s2 string) float64 {
+	a, b := symbols(s1), symbols(s2)
+	similarity := jaroSimilarity(a, b)
+	if similarity <= jw.BoostThreshold {
+		return similarity
+	}
+
+	prefix := 0
+	for prefix < min(4, len(a), len(b)) && a[prefix] == b[prefix] {
+		prefix++
+	}
+	return similarity + float64(prefix)*jw.PrefixScale*(1-similarity)
+}
+
+func (jw *JaroWinkler) Distance(s1, s2 string) float64 {
+	return 1 - jw.Similarity(s1, s2)
+}
@@ -0,0 +1,23 @@
+package metrics
+
+import "lb3_Levenshtein/vagner_fisher"
+
+// Metric compares two strings. Similarity lies in [0, 1] and is 1 for equal
+// strings; Distance is the metric's own distance, an edit count for the edit
+// based metrics and 1 - Similarity for the others.
+type Metric interface {
+	Name() string
+	Distance(s1, s2 string) float64
+	Similarity(s1, s2 string) float64
+}
+
+func symbols(s string) []string {
+	return vagner_fisher.Split(s, vagner_fisher.Runes)
+}
+
+func ratio(distance, bound float64) float64 {
+	if bound == 0 {
+		return 1
+	}
+	return 1 - distance/bound
+}
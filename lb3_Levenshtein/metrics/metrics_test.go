@@ -0,0 +1,54 @@
+package metrics
+
+import (
+	"math"
+	"testing"
+
+	"lb3_Levenshtein/vagner_fisher"
+)
+
+func TestMetrics(t *testing.T) {
+	tests := []struct {
+		metric     Metric
+		s1, s2     string
+		distance   float64
+		similarity float64
+	}{
+		{Jaro{}, "MARTHA", "MARHTA", 0.056, 0.944},
+		{Jaro{}, "DWAYNE", "DUANE", 0.178, 0.822},
+		{Jaro{}, "DIXON", "DICKSONX", 0.233, 0.767},
+		{NewJaroWinkler(), "MARTHA", "MARHTA", 0.039, 0.961},
+		{NewJaroWinkler(), "DWAYNE", "DUANE", 0.160, 0.840},
+		{NewJaroWinkler(), "DIXON", "DICKSONX", 0.187, 0.813},
+		{Jaro{}, "abc", "xyz", 1, 0},
+		{Hamming{}, "karolin", "kathrin", 3, 0.571},
+		{Hamming{}, "abc", "abcde", 2, 0.6},
+		{LCS{}, "abcde", "ace", 2, 0.75},
+		{&Levenshtein{}, "kitten", "sitting", 3, 0.571},
+		{NewLevenshtein(vagner_fisher.NewCostTable(2, 1, 1, 1)), "ab", "ba", 2, 0.5},
+		{QGram{}, "abc", "abd", 2, 0.5},
+		{QGram{Q: 3}, "abcd", "abce", 2, 0.5},
+		{Jaccard{}, "abc", "abd", 0.667, 0.333},
+		{Jaccard{}, "", "", 0, 1},
+	}
+	for _, test := range tests {
+		distance, similarity := test.metric.Distance(test.s1, test.s2), test.metric.Similarity(test.s1, test.s2)
+		if math.Abs(distance-test.distance) > 5e-4 || math.Abs(similarity-test.similarity) > 5e-4 {
+			t.Errorf("%s(%q, %q) = %.3f, %.3f, want %.3f, %.3f", test.metric.Name(), test.s1, test.s2,
+				distance, similarity, test.distance, test.similarity)
+		}
+	}
+}
+
+func TestLevenshteinZeroValue(t *testing.T) {
+	var zero Levenshtein
+	unit := NewLevenshtein(nil)
+	for _, pair := range [][2]string{{"a", "b"}, {"", "abc"}, {"flaw", "lawn"}} {
+		if got, want := zero.Distance(pair[0], pair[1]), unit.Distance(pair[0], pair[1]); got != want {
+			t.Errorf("zero Levenshtein.Distance(%q, %q) = %v, want %v", pair[0], pair[1], got, want)
+		}
+		if got, want := zero.Similarity(pair[0], pair[1]), unit.Similarity(pair[0], pair[1]); got != want {
+			t.Errorf("zero Levenshtein.Similarity(%q, %q) = %v, want %v", pair[0], pair[1], got, want)
+		}
+	}
+}
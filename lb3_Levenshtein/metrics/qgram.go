@@ -0,0 +1,93 @@
+package metrics
+
+import "strings"
+
+// defaultQ is the gram size of a QGram or Jaccard whose Q is below 1, which
+// includes the zero value.
+const defaultQ = 2
+
+func qgrams(s string, q int) map[string]int {
+	if q < 1 {
+		q = defaultQ
+	}
+	a := symbols(s)
+	grams := make(map[string]int)
+	for k := 0; k+q <= len(a); k++ {
+		grams[strings.Join(a[k:k+q], "")]++
+	}
+	return grams
+}
+
+// QGram is the q-gram distance of Ukkonen: the sum of absolute differences of
+// the q-gram counts of both strings. Q below 1 means bigrams.
+type QGram struct {
+	Q int
+}
+
+func (qg QGram) Name() string {
+	return "qgram"
+}
+
+func (qg QGram) counts(s1, s2 string) (distance, total int) {
+	a, b := qgrams(s1, qg.Q), qgrams(s2, qg.Q)
+	for gram, count := range a {
+		distance += max(count-b[gram], b[gram]-count)
+		total += count
+	}
+	for gram, count := range b {
+		if _, ok := a[gram]; !ok {
+			distance += count
+		}
+		total += count
+	}
+	return distance, total
+}
+
+func (qg QGram) Distance(s1, s2 string) float64 {
+	distance, _ := qg.counts(s1, s2)
+	return float64(distance)
+}
+
+func (qg QGram) Similarity(s1, s2 string) float64 {
+	if s1 == s2 {
+		return 1
+	}
+	distance, total := qg.counts(s1, s2)
+	if total == 0 {
+		return 0
+	}
+	return ratio(float64(distance), float64(total))
+}
+
+// Jaccard compares the sets of q-grams: |A ∩ B| / |A ∪ B|. Q below 1 means
+// bigrams.
+type Jaccard struct {
+	Q int
+}
+
+func (jc Jaccard) Name() string {
+	return "jaccard"
+}
+
+func (jc Jaccard) Similarity(s1, s2 string) float64 {
+	if s1 == s2 {
+		return 1
+	}
+	a, b := qgrams(s1, jc.Q), qgrams(s2, jc.Q)
+
+	common := 0
+	for gram := range a {
+		if _, ok := b[gram]; ok {
+			common++
+		}
+	}
+	union := len(a) + len(b) - common
+	if union == 0 {
+		return 0
+	}
+	return float64(common) / float64(union)
+}
+
+func (jc Jaccard) Distance(s1, s2 string) float64 {
+	return 1 - jc.Similarity(s1, s2)
+}
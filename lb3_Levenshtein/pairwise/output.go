@@ -0,0 +1,66 @@
+package pairwise
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+)
+
+// WriteCSV writes a header of the strings and one line per string. Cells
+// below the diagonal are left empty in triangle mode.
+func WriteCSV(w io.Writer, strs []string, opts Options) error {
+	out := csv.NewWriter(w)
+	if err := out.Write(append([]string{""}, strs...)); err != nil {
+		return err
+	}
+
+	err := Compute(strs, opts, func(row Row) error {
+		record := make([]string, len(strs)+1)
+		record[0] = strs[row.Index]
+		for k, distance := range row.Distances {
+			record[row.Offset+k+1] = strconv.Itoa(distance)
+		}
+		return out.Write(record)
+	})
+	if err != nil {
+		return err
+	}
+
+	out.Flush()
+	return out.Error()
+}
+
+// WriteJSON writes {"strings": [...], "distances": [[...], ...]}. Rows are
+// streamed as they are computed; in triangle mode row i lists the distances
+// to strings i+1 and later.
+func WriteJSON(w io.Writer, strs []string, opts Options) error {
+	header, err := json.Marshal(strs)
+	if err != nil {
+		return err
+	}
+	if _, err := fmt.Fprintf(w, "{\"strings\":%s,\"distances\":[", header); err != nil {
+		return err
+	}
+
+	err = Compute(strs, opts, func(row Row) error {
+		encoded, err := json.Marshal(row.Distances)
+		if err != nil {
+			return err
+		}
+		if row.Index > 0 {
+			if _, err := io.WriteString(w, ","); err != nil {
+				return err
+			}
+		}
+		_, err = w.Write(encoded)
+		return err
+	})
+	if err != nil {
+		return err
+	}
+
+	_, err = io.WriteString(w, "]}\n")
+	return err
+}
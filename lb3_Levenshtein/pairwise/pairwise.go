@@ -0,0 +1,143 @@
+package pairwise
+
+import (
+	"runtime"
+	"sync"
+
+	"lb3_Levenshtein/vagner_fisher"
+)
+
+type DistanceFunc func(s1, s2 string) int
+
+// Options configure Compute. The full matrix is mirrored from the upper
+// triangle unless Asymmetric is set, for distances where d(a, b) may differ
+// from d(b, a).
+type Options struct {
+	Workers    int
+	Triangle   bool
+	Asymmetric bool
+	Distance   DistanceFunc
+	Progress   func(rows, total int)
+}
+
+// Row holds the distances from Strings[Index] to Strings[Offset:]. Offset is
+// 0 for the full matrix and Index+1 for the upper triangle.
+type Row struct {
+	Index     int
+	Offset    int
+	Distances []int
+}
+
+// Compute fills the matrix row by row on a pool of workers and passes the rows
+// to emit in order. At most two rows per worker are held at any time, so in
+// triangle and asymmetric mode memory stays linear in the number of strings.
+// Mirroring the full matrix also keeps the part of every emitted row that
+// later rows still need, at most a quarter of the matrix. An error from emit
+// stops the workers and is returned.
+func Compute(strs []string, opts Options, emit func(Row) error) error {
+	workers := opts.Workers
+	if workers <= 0 {
+		workers = runtime.NumCPU()
+	}
+	distance := opts.Distance
+	if distance == nil {
+		distance = vagner_fisher.LevenshteinDistanceFunc(vagner_fisher.UnitCosts[string]{})
+	}
+
+	jobs := make(chan int)
+	results := make(chan Row, workers)
+	window := make(chan struct{}, 2*workers)
+	done := make(chan struct{})
+
+	go func() {
+		defer close(jobs)
+		for i := range strs {
+			select {
+			case window <- struct{}{}:
+			case <-done:
+				return
+			}
+			select {
+			case jobs <- i:
+			case <-done:
+				return
+			}
+		}
+	}()
+
+	var wg sync.WaitGroup
+	for range workers {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range jobs {
+				row := Row{Index: i}
+				switch {
+				case opts.Triangle:
+					row.Offset = i + 1
+				case !opts.Asymmetric:
+					row.Offset = i
+				}
+				row.Distances = make([]int, len(strs)-row.Offset)
+				for k := range row.Distances {
+					row.Distances[k] = distance(strs[i], strs[row.Offset+k])
+				}
+
+				select {
+				case results <- row:
+				case <-done:
+					return
+				}
+			}
+		}()
+	}
+
+	go func() {
+		wg.Wait()
+		close(results)
+	}()
+
+	mirror := !opts.Triangle && !opts.Asymmetric
+	var upper [][]int
+	pending := make(map[int]Row)
+	next := 0
+	var err error
+	for row := range results {
+		if err != nil {
+			continue
+		}
+
+		pending[row.Index] = row
+		for ready, ok := pending[next]; ok; ready, ok = pending[next] {
+			delete(pending, next)
+			if mirror {
+				ready = mirrorRow(ready, upper)
+				upper = append(upper, ready.Distances[next+1:])
+			}
+			if err = emit(ready); err != nil {
+				close(done)
+				break
+			}
+			<-window
+			next++
+			if opts.Progress != nil {
+				opts.Progress(next, len(strs))
+			}
+		}
+	}
+
+	return err
+}
+
+// mirrorRow completes row i of the full matrix, which holds the distances to
+// strings i and later, with the distances to earlier strings from the upper
+// rows emitted before it. The first distance of every upper row is dropped once
+// it is used, so each row only keeps the columns still to come.
+func mirrorRow(row Row, upper [][]int) Row {
+	distances := make([]int, 0, row.Index+len(row.Distances))
+	for j := range upper {
+		distances = append(distances, upper[j][0])
+		upper[j] = upper[j][1:]
+	}
+	return Row{Index: row.Index, Distances: append(distances, row.Distances...)}
+}
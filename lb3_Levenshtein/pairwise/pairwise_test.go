@@ -0,0 +1,129 @@
+package pairwise
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	"lb3_Levenshtein/vagner_fisher"
+)
+
+// asymmetric charges 2 per extra byte of s2 and 1 per extra byte of s1.
+func asymmetric(s1, s2 string) int {
+	if len(s1) < len(s2) {
+		return 2 * (len(s2) - len(s1))
+	}
+	return len(s1) - len(s2)
+}
+
+func testStrings(n int) []string {
+	strs := make([]string, n)
+	for i := range strs {
+		strs[i] = fmt.Sprintf("%x", i*i)
+	}
+	return strs
+}
+
+func TestComputeOrderAndValues(t *testing.T) {
+	strs := testStrings(40)
+	for _, opts := range []Options{
+		{},
+		{Triangle: true},
+		{Asymmetric: true, Distance: asymmetric},
+		{Triangle: true, Distance: asymmetric},
+	} {
+		distance := opts.Distance
+		if distance == nil {
+			distance = vagner_fisher.LevenshteinDistanceFunc(vagner_fisher.UnitCosts[string]{})
+		}
+		for _, workers := range []int{1, 3, 8} {
+			opts.Workers = workers
+			var calls atomic.Int64
+			counted := opts
+			counted.Distance = func(s1, s2 string) int {
+				calls.Add(1)
+				return distance(s1, s2)
+			}
+
+			next := 0
+			err := Compute(strs, counted, func(row Row) error {
+				if row.Index != next {
+					return fmt.Errorf("row %d emitted as row %d", row.Index, next)
+				}
+				next++
+				for k, got := range row.Distances {
+					j := row.Offset + k
+					if want := distance(strs[row.Index], strs[j]); got != want {
+						return fmt.Errorf("d(%d, %d) = %d, want %d", row.Index, j, got, want)
+					}
+				}
+				return nil
+			})
+			if err != nil || next != len(strs) {
+				t.Errorf("%+v: Compute emitted %d rows: %v", opts, next, err)
+			}
+
+			n := int64(len(strs))
+			want := n * (n + 1) / 2
+			switch {
+			case opts.Triangle:
+				want = n * (n - 1) / 2
+			case opts.Asymmetric:
+				want = n * n
+			}
+			if calls.Load() != want {
+				t.Errorf("%+v: %d distance calls, want %d", opts, calls.Load(), want)
+			}
+		}
+	}
+}
+
+func TestComputeStopsOnError(t *testing.T) {
+	strs := testStrings(200)
+	stop := errors.New("stop")
+	for _, workers := range []int{1, 2, 5} {
+		rows := 0
+		err := Compute(strs, Options{Workers: workers}, func(row Row) error {
+			rows++
+			if rows == 50 {
+				return stop
+			}
+			return nil
+		})
+		if err != stop || rows != 50 {
+			t.Errorf("workers %d: Compute = %v after %d rows, want %v after 50", workers, err, rows, stop)
+		}
+	}
+}
+
+func TestWriteCSV(t *testing.T) {
+	strs := []string{"kitten", "sitting", "mitten"}
+	var full, triangle bytes.Buffer
+	if err := WriteCSV(&full, strs, Options{}); err != nil {
+		t.Fatal(err)
+	}
+	if err := WriteCSV(&triangle, strs, Options{Triangle: true}); err != nil {
+		t.Fatal(err)
+	}
+
+	wantFull := ",kitten,sitting,mitten\nkitten,0,3,1\nsitting,3,0,3\nmitten,1,3,0\n"
+	wantTriangle := ",kitten,sitting,mitten\nkitten,,3,1\nsitting,,,3\nmitten,,,\n"
+	if full.String() != wantFull {
+		t.Errorf("WriteCSV = %q, want %q", full.String(), wantFull)
+	}
+	if triangle.String() != wantTriangle {
+		t.Errorf("WriteCSV triangle = %q, want %q", triangle.String(), wantTriangle)
+	}
+
+	var json bytes.Buffer
+	if err := WriteJSON(&json, strs, Options{Triangle: true}); err != nil {
+		t.Fatal(err)
+	}
+	want := `{"strings":["kitten","sitting","mitten"],"distances":[[3,1],[3],[]]}`
+	if got := strings.TrimSpace(json.String()); got != want {
+		t.Errorf("WriteJSON = %s, want %s", got, want)
+	}
+}
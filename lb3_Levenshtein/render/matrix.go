@@ -0,0 +1,173 @@
+package render
+
+import (
+	"fmt"
+	"html"
+	"io"
+	"strings"
+
+	"lb3_Levenshtein/vagner_fisher"
+)
+
+const cellSize = 48
+
+var opColors = map[rune]string{
+	vagner_fisher.Match:     "#d9f2d9",
+	vagner_fisher.Replace:   "#fff2cc",
+	vagner_fisher.Insert:    "#d6e4ff",
+	vagner_fisher.Delete:    "#ffd9d9",
+	vagner_fisher.Transpose: "#ecdcff",
+}
+
+// matrix is the final state of a replayed trace with the cells at which a
+// special rune was priced.
+type matrix struct {
+	state   vagner_fisher.ReplayState
+	special map[vagner_fisher.Cell]bool
+}
+
+func replayMatrix(trace *vagner_fisher.Trace) (*matrix, error) {
+	m := &matrix{special: make(map[vagner_fisher.Cell]bool)}
+	for state, err := range trace.Replay() {
+		if err != nil {
+			return nil, err
+		}
+		if state.Event.Kind == vagner_fisher.EventSpecial {
+			m.special[vagner_fisher.Cell{I: state.Event.I, J: state.Event.J}] = true
+		}
+		m.state = *state
+	}
+	if m.state.DP == nil {
+		return nil, fmt.Errorf("trace has no init event")
+	}
+	return m, nil
+}
+
+// WriteMatrixSVG draws the DP of a trace as an SVG image. Rows are labelled
+// with the symbols of s1 and columns with those of s2, every cell shows its
+// cost and chosen op on the op's colour, cells priced with a special rune get
+// an orange border and the backtracked path is drawn through the grid.
+func WriteMatrixSVG(w io.Writer, trace *vagner_fisher.Trace) error {
+	m, err := replayMatrix(trace)
+	if err != nil {
+		return err
+	}
+	_, err = io.WriteString(w, m.svg())
+	return err
+}
+
+// WriteMatrixHTML writes a standalone HTML page with the SVG of
+// WriteMatrixSVG, the distance and a legend of the colours.
+func WriteMatrixHTML(w io.Writer, trace *vagner_fisher.Trace) error {
+	m, err := replayMatrix(trace)
+	if err != nil {
+		return err
+	}
+
+	source := strings.Join(m.state.Source, "")
+	target := strings.Join(m.state.Target, "")
+	title := html.EscapeString(fmt.Sprintf("%q → %q", source, target))
+
+	var b strings.Builder
+	b.WriteString("<!DOCTYPE html>\n<html>\n<head>\n<meta charset=\"utf-8\">\n")
+	fmt.Fprintf(&b, "<title>%s</title>\n", title)
+	b.WriteString("<style>body{font-family:sans-serif;margin:2em}.legend span{display:inline-block;" +
+		"padding:0.2em 0.6em;margin-right:0.5em;border:1px solid #999}</style>\n</head>\n<body>\n")
+	fmt.Fprintf(&b, "<h1>%s</h1>\n", title)
+	if m.state.Done {
+		fmt.Fprintf(&b, "<p>Distance: %d, operations: %s</p>\n", m.state.Distance, html.EscapeString(m.state.Event.Path))
+	}
+	b.WriteString("<p class=\"legend\">")
+	for _, op := range []rune{vagner_fisher.Match, vagner_fisher.Replace, vagner_fisher.Insert, vagner_fisher.Delete,
+		vagner_fisher.Transpose} {
+		fmt.Fprintf(&b, "<span style=\"background:%s\">%c</span>", opColors[op], op)
+	}
+	b.WriteString("<span style=\"border:3px solid #ff8c00\">special rune</span></p>\n")
+	b.WriteString(m.svg())
+	b.WriteString("</body>\n</html>\n")
+
+	_, err = io.WriteString(w, b.String())
+	return err
+}
+
+// center returns the middle of DP cell (i, j). Row and column 0 of the image
+// hold the labels, so cell (i, j) is drawn at grid position (i+1, j+1).
+func center(i, j int) (int, int) {
+	return (j+1)*cellSize + cellSize/2, (i+1)*cellSize + cellSize/2
+}
+
+func (m *matrix) svg() string {
+	dp, ops := m.state.DP, m.state.Ops
+	rows, cols := len(dp), len(dp[0])
+	width, height := (cols+1)*cellSize, (rows+1)*cellSize
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "<svg xmlns=\"http://www.w3.org/2000/svg\" width=\"%d\" height=\"%d\" viewBox=\"0 0 %d %d\" "+
+		"font-family=\"monospace\" text-anchor=\"middle\" dominant-baseline=\"central\">\n", width, height, width, height)
+
+	label := func(x, y int, text string) {
+		fmt.Fprintf(&b, "<text x=\"%d\" y=\"%d\" font-size=\"18\" font-weight=\"bold\">%s</text>\n",
+			x, y, html.EscapeString(text))
+	}
+	for j := range cols {
+		x, _ := center(0, j)
+		text := "ε"
+		if j > 0 {
+			text = m.state.Target[j-1]
+		}
+		label(x, cellSize/2, text)
+	}
+	for i := range rows {
+		_, y := center(i, 0)
+		text := "ε"
+		if i > 0 {
+			text = m.state.Source[i-1]
+		}
+		label(cellSize/2, y, text)
+	}
+
+	for i := range rows {
+		for j := range cols {
+			fill, ok := opColors[ops[i][j]]
+			if !ok {
+				fill = "#ffffff"
+			}
+			stroke, strokeWidth := "#999999", 1
+			if m.special[vagner_fisher.Cell{I: i, J: j}] {
+				stroke, strokeWidth = "#ff8c00", 3
+			}
+			fmt.Fprintf(&b, "<rect x=\"%d\" y=\"%d\" width=\"%d\" height=\"%d\" fill=\"%s\" stroke=\"%s\" stroke-width=\"%d\"/>\n",
+				(j+1)*cellSize, (i+1)*cellSize, cellSize, cellSize, fill, stroke, strokeWidth)
+		}
+	}
+
+	// The path goes under the numbers so that they stay readable.
+	if len(m.state.Path) > 1 {
+		points := make([]string, len(m.state.Path))
+		for k, cell := range m.state.Path {
+			x, y := center(cell.I, cell.J)
+			points[k] = fmt.Sprintf("%d,%d", x, y)
+		}
+		fmt.Fprintf(&b, "<polyline points=\"%s\" fill=\"none\" stroke=\"#cc0000\" stroke-width=\"3\" "+
+			"stroke-opacity=\"0.5\" stroke-linejoin=\"round\"/>\n", strings.Join(points, " "))
+	}
+	for _, cell := range m.state.Path {
+		x, y := center(cell.I, cell.J)
+		fmt.Fprintf(&b, "<circle cx=\"%d\" cy=\"%d\" r=\"14\" fill=\"#ffffff\" stroke=\"#cc0000\" stroke-width=\"2\"/>\n", x, y)
+	}
+
+	for i := range rows {
+		for j := range cols {
+			if ops[i][j] == 0 {
+				continue
+			}
+			cx, cy := center(i, j)
+			fmt.Fprintf(&b, "<text x=\"%d\" y=\"%d\" font-size=\"16\">%d</text>\n", cx, cy, dp[i][j])
+			fmt.Fprintf(&b, "<text x=\"%d\" y=\"%d\" font-size=\"10\" fill=\"#555555\">%c</text>\n",
+				(j+2)*cellSize-7, (i+2)*cellSize-7, ops[i][j])
+		}
+	}
+
+	b.WriteString("</svg>\n")
+	return b.String()
+}
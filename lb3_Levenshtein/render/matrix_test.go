@@ -0,0 +1,118 @@
+package render
+
+import (
+	"bytes"
+	"encoding/xml"
+	"io"
+	"strings"
+	"testing"
+
+	"lb3_Levenshtein/vagner_fisher"
+)
+
+// svgElements parses an SVG document and counts its elements by name, with the
+// texts of its text elements in order.
+func svgElements(t *testing.T, svg string) (map[string]int, []string) {
+	t.Helper()
+	counts := make(map[string]int)
+	var texts []string
+	decoder := xml.NewDecoder(strings.NewReader(svg))
+	for {
+		token, err := decoder.Token()
+		if err == io.EOF {
+			return counts, texts
+		}
+		if err != nil {
+			t.Fatalf("invalid SVG: %v\n%s", err, svg)
+		}
+		switch token := token.(type) {
+		case xml.StartElement:
+			counts[token.Name.Local]++
+			if token.Name.Local == "rect" {
+				for _, attr := range token.Attr {
+					if attr.Name.Local == "stroke" && attr.Value == "#ff8c00" {
+						counts["special"]++
+					}
+				}
+			}
+		case xml.CharData:
+			if text := strings.TrimSpace(string(token)); text != "" {
+				texts = append(texts, text)
+			}
+		}
+	}
+}
+
+func traceOf(s1, s2 string, costs vagner_fisher.CostModel) *vagner_fisher.Trace {
+	trace := &vagner_fisher.Trace{}
+	vagner_fisher.FindEditScript(s1, s2, vagner_fisher.Runes, vagner_fisher.NoTransposition, costs, trace)
+	return trace
+}
+
+func TestWriteMatrixSVG(t *testing.T) {
+	costs := vagner_fisher.NewClassicCosts(&vagner_fisher.OperationCosts{Replace: 1, Insert: 1, Delete: 1,
+		SpecialReplace: 2, SpecialInsert: 1}, &vagner_fisher.SpecialRunes{Replace: '<', Insert: '\x00'})
+	var out bytes.Buffer
+	if err := WriteMatrixSVG(&out, traceOf("<a", "ab", costs)); err != nil {
+		t.Fatal(err)
+	}
+
+	counts, texts := svgElements(t, out.String())
+	// Deleting '<' is cheaper than replacing it, so the path is DMI.
+	if counts["svg"] != 1 || counts["rect"] != 9 || counts["polyline"] != 1 {
+		t.Errorf("SVG has %d svg, %d rect and %d polyline elements, want 1, 9 and 1", counts["svg"], counts["rect"],
+			counts["polyline"])
+	}
+	if counts["circle"] != 4 {
+		t.Errorf("SVG marks %d path cells, want 4", counts["circle"])
+	}
+	// '<' is priced against both symbols of s2.
+	if counts["special"] != 2 {
+		t.Errorf("SVG marks %d special cells, want 2", counts["special"])
+	}
+	// Six labels, and a cost and an op in every cell.
+	if counts["text"] != 6+2*9 {
+		t.Errorf("SVG has %d texts, want %d", counts["text"], 6+2*9)
+	}
+	labels := strings.Join(texts[:6], " ")
+	if labels != "ε a b ε < a" {
+		t.Errorf("SVG labels are %q, want the symbols of s2 and then s1", labels)
+	}
+}
+
+func TestWriteMatrixHTML(t *testing.T) {
+	var out bytes.Buffer
+	if err := WriteMatrixHTML(&out, traceOf("a&b", "ab", vagner_fisher.NewCostTable(1, 1, 1, 1))); err != nil {
+		t.Fatal(err)
+	}
+	page := out.String()
+
+	for _, want := range []string{
+		"<!DOCTYPE html>",
+		"<title>&#34;a&amp;b&#34; → &#34;ab&#34;</title>",
+		"<p>Distance: 1, operations: MDM</p>",
+		"<span style=\"background:#ffd9d9\">D</span>",
+	} {
+		if !strings.Contains(page, want) {
+			t.Errorf("page lacks %q:\n%s", want, page)
+		}
+	}
+
+	start, end := strings.Index(page, "<svg"), strings.Index(page, "</svg>")
+	if start < 0 || end < start {
+		t.Fatalf("page has no SVG:\n%s", page)
+	}
+	if counts, _ := svgElements(t, page[start:end+len("</svg>")]); counts["rect"] != 4*3 {
+		t.Errorf("SVG has %d cells, want 12", counts["rect"])
+	}
+}
+
+func TestWriteMatrixWithoutInit(t *testing.T) {
+	trace := &vagner_fisher.Trace{}
+	if err := WriteMatrixSVG(io.Discard, trace); err == nil {
+		t.Error("WriteMatrixSVG of an empty trace succeeded")
+	}
+	if err := WriteMatrixHTML(io.Discard, trace); err == nil {
+		t.Error("WriteMatrixHTML of an empty trace succeeded")
+	}
+}
@@ -0,0 +1,119 @@
+package render
+
+import (
+	"strings"
+
+	"lb3_Levenshtein/vagner_fisher"
+)
+
+const gap = "-"
+
+type column struct {
+	source, op, target string
+	width              int
+}
+
+func columns(script vagner_fisher.EditScript) []column {
+	var cols []column
+	for _, e := range script.Alignment() {
+		col := column{source: e.From, op: string(e.Op), target: e.To}
+		switch e.Op {
+		case vagner_fisher.Match:
+			col.op = "|"
+		case vagner_fisher.Insert:
+			col.source = gap
+		case vagner_fisher.Delete:
+			col.target = gap
+		}
+		col.width = max(1, symbolWidth(col.source), symbolWidth(col.target))
+		if col.source == gap {
+			col.source = strings.Repeat(gap, col.width)
+		}
+		if col.target == gap {
+			col.target = strings.Repeat(gap, col.width)
+		}
+		cols = append(cols, col)
+	}
+	return cols
+}
+
+func pad(b *strings.Builder, symbol string, width int) {
+	b.WriteString(symbol)
+	b.WriteString(strings.Repeat(" ", width-symbolWidth(symbol)))
+}
+
+func rows(cols []column) [3]string {
+	var source, ops, target strings.Builder
+	for k, col := range cols {
+		if k > 0 {
+			source.WriteByte(' ')
+			ops.WriteByte(' ')
+			target.WriteByte(' ')
+		}
+		pad(&source, col.source, col.width)
+		pad(&ops, col.op, col.width)
+		pad(&target, col.target, col.width)
+	}
+	return [3]string{
+		strings.TrimRight(source.String(), " "),
+		strings.TrimRight(ops.String(), " "),
+		strings.TrimRight(target.String(), " "),
+	}
+}
+
+// Align draws the script as three rows: s1 with gaps, the operations ('|' for
+// a match) and s2 with gaps. Columns are padded to the display width of their
+// widest symbol.
+func Align(script vagner_fisher.EditScript) string {
+	lines := rows(columns(script))
+	return strings.Join(lines[:], "\n")
+}
+
+// Wrap is Align split into blocks no wider than width cells, separated by
+// empty lines. A column wider than width still gets a block of its own.
+func Wrap(script vagner_fisher.EditScript, width int) string {
+	cols := columns(script)
+	var blocks []string
+
+	for start := 0; start < len(cols); {
+		end, used := start+1, cols[start].width
+		for end < len(cols) && used+1+cols[end].width <= width {
+			used += 1 + cols[end].width
+			end++
+		}
+		lines := rows(cols[start:end])
+		blocks = append(blocks, strings.Join(lines[:], "\n"))
+		start = end
+	}
+
+	return strings.Join(blocks, "\n\n")
+}
+
+// Diff marks every run of differing columns inline as [-removed-]{+added+}
+// and copies matches through unchanged.
+func Diff(script vagner_fisher.EditScript) string {
+	var out, removed, added strings.Builder
+	flush := func() {
+		if removed.Len() > 0 {
+			out.WriteString("[-" + removed.String() + "-]")
+		}
+		if added.Len() > 0 {
+			out.WriteString("{+" + added.String() + "+}")
+		}
+		removed.Reset()
+		added.Reset()
+	}
+
+	for _, e := range script.Alignment() {
+		if e.Op == vagner_fisher.Match {
+			flush()
+			out.WriteString(e.From)
+			continue
+		}
+		removed.WriteString(e.From)
+		added.WriteString(e.To)
+	}
+	flush()
+
+	return out.String()
+}
@@ -0,0 +1,52 @@
+package render
+
+import (
+	"unicode"
+	"unicode/utf8"
+)
+
+var wideRanges = [][2]rune{
+	{0x1100, 0x115F},
+	{0x231A, 0x231B},
+	{0x2E80, 0x303E},
+	{0x3041, 0x33FF},
+	{0x3400, 0x4DBF},
+	{0x4E00, 0x9FFF},
+	{0xA000, 0xA4CF},
+	{0xAC00, 0xD7A3},
+	{0xF900, 0xFAFF},
+	{0xFE30, 0xFE4F},
+	{0xFF00, 0xFF60},
+	{0xFFE0, 0xFFE6},
+	{0x1F300, 0x1F64F},
+	{0x1F680, 0x1F6FF},
+	{0x1F900, 0x1F9FF},
+	{0x20000, 0x3FFFD},
+}
+
+func runeWidth(r rune) int {
+	switch {
+	case unicode.In(r, unicode.Mn, unicode.Me, unicode.Cc, unicode.Cf):
+		return 0
+	case r >= 0x1160 && r <= 0x11FF:
+		return 0
+	}
+	for _, wide := range wideRanges {
+		if r >= wide[0] && r <= wide[1] {
+			return 2
+		}
+	}
+	return 1
+}
+
+// symbolWidth is the number of terminal cells a symbol occupies. A grapheme
+// cluster is drawn as one glyph, so it takes the width of its widest rune.
+func symbolWidth(symbol string) int {
+	width := 0
+	for len(symbol) > 0 {
+		r, size := utf8.DecodeRuneInString(symbol)
+		width = max(width, runeWidth(r))
+		symbol = symbol[size:]
+	}
+	return width
+}
@@ -0,0 +1,217 @@
+package suggest
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"slices"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"lb3_Levenshtein/bktree"
+	"lb3_Levenshtein/logger"
+	"lb3_Levenshtein/vagner_fisher"
+)
+
+// Dictionary is a word list with optional frequencies. Words are stored in
+// lower case.
+type Dictionary struct {
+	Words     []string
+	Frequency map[string]int
+}
+
+type Suggestion struct {
+	Word      string
+	Distance  int
+	Frequency int
+}
+
+// Suggester ranks dictionary words by their distance to a misspelled word
+// under the configured costs, and by frequency among equal distances. The
+// dictionary is indexed once: unit costs search a trie with a Levenshtein
+// automaton, other costs that make the distance a metric a BK-tree. Costs
+// that do not are checked against every word with the bounded distance.
+type Suggester struct {
+	dict        *Dictionary
+	costs       vagner_fisher.CostModel
+	maxDistance int
+	trie        *vagner_fisher.Trie
+	tree        *bktree.Tree
+	log         vagner_fisher.Logger
+}
+
+func LoadDictionary(path string) (*Dictionary, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	dict, err := ParseDictionary(file)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return dict, nil
+}
+
+// ParseDictionary reads one word per line, optionally followed by its
+// frequency. Words without a frequency count as 1.
+func ParseDictionary(r io.Reader) (*Dictionary, error) {
+	dict := &Dictionary{Frequency: make(map[string]int)}
+	scanner := bufio.NewScanner(r)
+
+	for line := 1; scanner.Scan(); line++ {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) == 0 || strings.HasPrefix(fields[0], "#") {
+			continue
+		}
+		if len(fields) > 2 {
+			return nil, fmt.Errorf("line %d: expected a word and an optional frequency", line)
+		}
+
+		frequency := 1
+		if len(fields) == 2 {
+			var err error
+			if frequency, err = strconv.Atoi(fields[1]); err != nil {
+				return nil, fmt.Errorf("line %d: invalid frequency: %w", line, err)
+			}
+		}
+
+		word := strings.ToLower(fields[0])
+		if _, ok := dict.Frequency[word]; !ok {
+			dict.Words = append(dict.Words, word)
+		}
+		dict.Frequency[word] += frequency
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	slices.Sort(dict.Words)
+	return dict, nil
+}
+
+func (d *Dictionary) Contains(word string) bool {
+	_, ok := d.Frequency[strings.ToLower(word)]
+	return ok
+}
+
+func NewSuggester(dict *Dictionary, costs vagner_fisher.CostModel, maxDistance int) *Suggester {
+	s := &Suggester{
+		dict:        dict,
+		costs:       costs,
+		maxDistance: maxDistance,
+		log:         logger.Nop{},
+	}
+
+	if u, ok := costs.(vagner_fisher.UnitCost); ok && u.Unit() {
+		s.trie = vagner_fisher.NewTrie(dict.Words)
+	} else if isMetric(costs) {
+		s.tree = bktree.New(vagner_fisher.LevenshteinDistanceFunc(costs))
+		for _, word := range dict.Words {
+			s.tree.Insert(word)
+		}
+	}
+	return s
+}
+
+// isMetric reports whether the edit distance under costs is symmetric, which
+// with the triangle inequality every edit distance obeys is what a BK-tree
+// needs: inserts cost the same as deletes and replacements the same both ways.
+// Only the models of vagner_fisher are recognised.
+func isMetric(costs vagner_fisher.CostModel) bool {
+	switch c := costs.(type) {
+	case *vagner_fisher.ClassicCosts:
+		return c.Costs.Insert == c.Costs.Delete &&
+			(c.Runes.Replace == 0 || c.Costs.SpecialReplace == c.Costs.Replace) &&
+			(c.Runes.Insert == 0 || c.Costs.SpecialInsert == c.Costs.Insert)
+	case *vagner_fisher.CostTable:
+		if c.DefaultInsert != c.DefaultDelete {
+			return false
+		}
+		for symbol, cost := range c.Insert {
+			if c.DeleteCost(symbol) != cost {
+				return false
+			}
+		}
+		for symbol, cost := range c.Delete {
+			if c.InsertCost(symbol) != cost {
+				return false
+			}
+		}
+		for pair, cost := range c.Replace {
+			if c.ReplaceCost(pair[1], pair[0]) != cost {
+				return false
+			}
+		}
+		return true
+	}
+	return false
+}
+
+// candidates returns the dictionary words within the maximum distance of word.
+func (s *Suggester) candidates(word string) []Suggestion {
+	if s.maxDistance < 0 {
+		return nil
+	}
+
+	var suggestions []Suggestion
+	add := func(candidate string, distance int) {
+		suggestions = append(suggestions, Suggestion{candidate, distance, s.dict.Frequency[candidate]})
+	}
+
+	switch {
+	case s.trie != nil:
+		automaton, err := vagner_fisher.NewLevenshteinAutomaton(word, s.maxDistance, vagner_fisher.NoTransposition)
+		if err != nil {
+			return nil
+		}
+		for _, match := range automaton.SearchTrie(s.trie) {
+			add(match.Word, match.Distance)
+		}
+	case s.tree != nil:
+		for _, result := range s.tree.Within(word, s.maxDistance) {
+			add(result.Word, result.Distance)
+		}
+	default:
+		for _, candidate := range s.dict.Words {
+			if distance, ok := vagner_fisher.FindLevenshteinDistanceBounded(word, candidate, vagner_fisher.Runes, s.maxDistance, s.costs, s.log); ok {
+				add(candidate, distance)
+			}
+		}
+	}
+	return suggestions
+}
+
+// Suggest returns up to n corrections within the maximum distance, closest
+// first, then most frequent, then alphabetical.
+func (s *Suggester) Suggest(word string, n int) []Suggestion {
+	suggestions := s.candidates(strings.ToLower(word))
+
+	slices.SortFunc(suggestions, func(x, y Suggestion) int {
+		if x.Distance != y.Distance {
+			return x.Distance - y.Distance
+		}
+		if x.Frequency != y.Frequency {
+			return y.Frequency - x.Frequency
+		}
+		return strings.Compare(x.Word, y.Word)
+	})
+
+	return suggestions[:min(n, len(suggestions))]
+}
+
+// Words splits text into words: runs of letters, with apostrophes inside.
+func Words(text string) []string {
+	var words []string
+	for _, field := range strings.FieldsFunc(text, func(r rune) bool {
+		return !unicode.IsLetter(r) && r != '\''
+	}) {
+		if word := strings.Trim(field, "'"); word != "" {
+			words = append(words, word)
+		}
+	}
+	return words
+}
@@ -0,0 +1,111 @@
+package suggest
+
+import (
+	"slices"
+	"strings"
+	"testing"
+
+	"lb3_Levenshtein/vagner_fisher"
+)
+
+const testDictionary = `# word frequency
+hello 5
+help 2
+hell
+Held 2
+world
+word 3
+shell 4
+`
+
+func loadTestDictionary(t *testing.T) *Dictionary {
+	dict, err := ParseDictionary(strings.NewReader(testDictionary))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return dict
+}
+
+func TestSuggestRanking(t *testing.T) {
+	dict := loadTestDictionary(t)
+	suggester := NewSuggester(dict, vagner_fisher.NewCostTable(1, 1, 1, 1), 2)
+	tests := []struct {
+		word string
+		n    int
+		want []Suggestion
+	}{
+		// Equal distances rank by frequency, then alphabetically.
+		{"helo", 3, []Suggestion{{"hello", 1, 5}, {"held", 1, 2}, {"help", 1, 2}}},
+		{"Helo", 5, []Suggestion{{"hello", 1, 5}, {"held", 1, 2}, {"help", 1, 2}, {"hell", 1, 1}, {"shell", 2, 4}}},
+		{"wrld", 5, []Suggestion{{"world", 1, 1}, {"word", 2, 3}, {"held", 2, 2}}},
+		{"xyzzy", 5, nil},
+		{"helo", 0, nil},
+	}
+	for _, test := range tests {
+		if got := suggester.Suggest(test.word, test.n); !slices.Equal(got, test.want) {
+			t.Errorf("Suggest(%q, %d) = %v, want %v", test.word, test.n, got, test.want)
+		}
+	}
+}
+
+func TestSuggestMatchesBruteForce(t *testing.T) {
+	dict := loadTestDictionary(t)
+	costs := []vagner_fisher.CostModel{
+		vagner_fisher.NewCostTable(1, 1, 1, 1),
+		// A metric, searched with the BK-tree.
+		vagner_fisher.NewCostTable(2, 1, 1, 1),
+		// Not a metric, checked word by word.
+		vagner_fisher.NewCostTable(1, 1, 2, 1),
+	}
+	for _, cost := range costs {
+		for maxDistance := 0; maxDistance <= 3; maxDistance++ {
+			suggester := NewSuggester(dict, cost, maxDistance)
+			for _, word := range []string{"helo", "helloo", "wrld", "shel", "ehll", ""} {
+				var want []Suggestion
+				for _, candidate := range dict.Words {
+					distance, _ := vagner_fisher.FindLevenshteinDistance(word, candidate, cost, nil)
+					if distance <= maxDistance {
+						want = append(want, Suggestion{candidate, distance, dict.Frequency[candidate]})
+					}
+				}
+				slices.SortFunc(want, func(x, y Suggestion) int {
+					if x.Distance != y.Distance {
+						return x.Distance - y.Distance
+					}
+					if x.Frequency != y.Frequency {
+						return y.Frequency - x.Frequency
+					}
+					return strings.Compare(x.Word, y.Word)
+				})
+
+				if got := suggester.Suggest(word, len(dict.Words)); !slices.Equal(got, want) {
+					t.Errorf("%v, max %d: Suggest(%q) = %v, want %v", cost, maxDistance, word, got, want)
+				}
+			}
+		}
+	}
+}
+
+func TestParseDictionary(t *testing.T) {
+	dict := loadTestDictionary(t)
+	if want := []string{"held", "hell", "hello", "help", "shell", "word", "world"}; !slices.Equal(dict.Words, want) {
+		t.Errorf("Words = %v, want %v", dict.Words, want)
+	}
+	if !dict.Contains("HELD") || dict.Frequency["held"] != 2 || dict.Frequency["hell"] != 1 {
+		t.Errorf("Held is not stored in lower case with its frequency: %v", dict.Frequency)
+	}
+
+	for _, bad := range []string{"word 1 2\n", "word many\n"} {
+		if _, err := ParseDictionary(strings.NewReader(bad)); err == nil {
+			t.Errorf("ParseDictionary(%q) accepts an invalid line", bad)
+		}
+	}
+}
+
+func TestWords(t *testing.T) {
+	got := Words("It's a 'quoted' test, isn't it? Ünïcode-words")
+	want := []string{"It's", "a", "quoted", "test", "isn't", "it", "Ünïcode", "words"}
+	if !slices.Equal(got, want) {
+		t.Errorf("Words = %q, want %q", got, want)
+	}
+}
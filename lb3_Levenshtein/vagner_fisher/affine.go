@@ -0,0 +1,157 @@
+package vagner_fisher
+
+import (
+	"fmt"
+
+	"lb3_Levenshtein/logger"
+)
+
+// AffineGaps prices a run of L inserts or deletes as Open + L*Extend instead
+// of the per-symbol insert and delete costs of the cost model.
+type AffineGaps struct {
+	Open   int
+	Extend int
+}
+
+// The Gotoh DP keeps one matrix per kind of last edit. They are indexed by the
+// operation rune that minOperation returns for the same slot, so ties are
+// broken the same way as in the classic DP.
+var affineStates = [3]rune{Replace, Insert, Delete}
+
+func affineState(op rune) int {
+	switch op {
+	case Insert:
+		return 1
+	case Delete:
+		return 2
+	}
+	return 0
+}
+
+type affineMatrices struct {
+	dp   [3][][]int
+	from [3][][]rune
+}
+
+func FindLevenshteinDistanceAffine(s1, s2 string, seg Segmentation, gaps AffineGaps, costs CostModel, log Logger) (int, string) {
+	distance, script := FindAffineEditScript(s1, s2, seg, gaps, costs, log)
+	return distance, script.String()
+}
+
+func FindAffineEditScript(s1, s2 string, seg Segmentation, gaps AffineGaps, costs CostModel, log Logger) (int, EditScript) {
+	log, costs = orDiscard(log), orUnit(costs)
+	a, b := Split(s1, seg), Split(s2, seg)
+	n, m := len(a), len(b)
+
+	log.LogMsg("Init", fmt.Sprintf("Calculating affine distance between '%s' (%d) and '%s' (%d), gap open %d, extend %d",
+		s1, n, s2, m, gaps.Open, gaps.Extend),
+		logger.ColorCyan)
+
+	am := fillAffineMatrices(a, b, gaps, costs)
+
+	log.LogCostMatrix("Replace DP", am.dp[0], logger.ColorRed)
+	log.LogCostMatrix("Insert DP", am.dp[1], logger.ColorRed)
+	log.LogCostMatrix("Delete DP", am.dp[2], logger.ColorRed)
+
+	state, distance := minOperation(am.dp[0][n][m], am.dp[1][n][m], am.dp[2][n][m])
+	script := am.buildPath(a, b, state, gaps)
+
+	log.LogMsg("Result", fmt.Sprintf("Final distance: %d, Path: %s", distance, script),
+		logger.ColorGreen)
+
+	return distance, script
+}
+
+func fillAffineMatrices(a, b []string, gaps AffineGaps, costs CostModel) *affineMatrices {
+	n, m := len(a), len(b)
+	am := &affineMatrices{}
+	for s := range affineStates {
+		am.dp[s] = make([][]int, n+1)
+		am.from[s] = make([][]rune, n+1)
+		for i := range am.dp[s] {
+			am.dp[s][i] = make([]int, m+1)
+			am.from[s][i] = make([]rune, m+1)
+			for j := range am.dp[s][i] {
+				am.dp[s][i][j] = unreachable
+			}
+		}
+	}
+
+	open := gaps.Open + gaps.Extend
+	am.dp[0][0][0] = 0
+	for j := 1; j <= m; j++ {
+		am.dp[1][0][j] = gaps.Open + j*gaps.Extend
+		am.from[1][0][j] = Insert
+		if j == 1 {
+			am.from[1][0][j] = Replace
+		}
+	}
+	for i := 1; i <= n; i++ {
+		am.dp[2][i][0] = gaps.Open + i*gaps.Extend
+		am.from[2][i][0] = Delete
+		if i == 1 {
+			am.from[2][i][0] = Replace
+		}
+	}
+
+	for i := 1; i <= n; i++ {
+		for j := 1; j <= m; j++ {
+			replace := 0
+			if a[i-1] != b[j-1] {
+				replace = costs.ReplaceCost(a[i-1], b[j-1])
+			}
+			op, cost := minOperation(am.dp[0][i-1][j-1], am.dp[1][i-1][j-1], am.dp[2][i-1][j-1])
+			am.dp[0][i][j], am.from[0][i][j] = min(cost+replace, unreachable), op
+
+			op, cost = minOperation(am.dp[0][i][j-1]+open, am.dp[1][i][j-1]+gaps.Extend, am.dp[2][i][j-1]+open)
+			am.dp[1][i][j], am.from[1][i][j] = min(cost, unreachable), op
+
+			op, cost = minOperation(am.dp[0][i-1][j]+open, am.dp[1][i-1][j]+open, am.dp[2][i-1][j]+gaps.Extend)
+			am.dp[2][i][j], am.from[2][i][j] = min(cost, unreachable), op
+		}
+	}
+
+	return am
+}
+
+// buildPath walks back through the matrices. A gap edit that starts its run
+// carries the open cost, so the script cost adds up to the distance.
+func (am *affineMatrices) buildPath(a, b []string, state rune, gaps AffineGaps) EditScript {
+	var script EditScript
+	i, j := len(a), len(b)
+
+	for i > 0 || j > 0 {
+		prev := am.from[affineState(state)][i][j]
+		e := Edit{Op: state}
+
+		switch state {
+		case Insert:
+			j--
+			e.To, e.Cost = b[j], gaps.Extend
+		case Delete:
+			i--
+			e.From, e.Cost = a[i], gaps.Extend
+		default:
+			i--
+			j--
+			e.From, e.To = a[i], b[j]
+			e.Cost = am.dp[0][i+1][j+1] - am.dp[affineState(prev)][i][j]
+			if e.From == e.To {
+				e.Op = Match
+			}
+		}
+		if state != Replace && prev != state {
+			e.Cost += gaps.Open
+		}
+
+		e.SourceIndex, e.TargetIndex = i, j
+		script = append(script, e)
+		state = prev
+	}
+
+	for k := 0; k < len(script)/2; k++ {
+		script[k], script[len(script)-1-k] = script[len(script)-1-k], script[k]
+	}
+
+	return script
+}
@@ -0,0 +1,68 @@
+package vagner_fisher
+
+import "testing"
+
+func TestAffineScriptCostsDistance(t *testing.T) {
+	gaps := []AffineGaps{{0, 1}, {2, 1}, {5, 1}, {1, 3}}
+	for _, g := range gaps {
+		for _, cc := range testCosts {
+			for _, pair := range testPairs {
+				s1, s2 := pair[0], pair[1]
+				distance, script := FindAffineEditScript(s1, s2, Runes, g, cc.costs, quietLogger())
+				if err := script.Validate(s1, s2, nil, distance); err != nil {
+					t.Errorf("%s %+v: FindAffineEditScript(%q, %q): %v", cc.name, g, s1, s2, err)
+				}
+			}
+		}
+	}
+}
+
+func TestAffineWithoutOpenIsClassic(t *testing.T) {
+	unit := testCosts[0].costs
+	for _, pair := range testPairs {
+		s1, s2 := pair[0], pair[1]
+		want, _ := FindLevenshteinDistance(s1, s2, unit, debugLogger())
+		if got, _ := FindAffineEditScript(s1, s2, Runes, AffineGaps{0, 1}, unit, quietLogger()); got != want {
+			t.Errorf("FindAffineEditScript(%q, %q) with free gap opens = %d, want %d", s1, s2, got, want)
+		}
+	}
+}
+
+func TestAffineOnGraphemes(t *testing.T) {
+	unit := testCosts[0].costs
+	for _, pair := range graphemePairs {
+		s1, s2 := pair[0], pair[1]
+		want, _ := FindLevenshteinDistanceSegmented(s1, s2, Graphemes, unit, debugLogger())
+		distance, script := FindAffineEditScript(s1, s2, Graphemes, AffineGaps{0, 1}, unit, quietLogger())
+		if distance != want {
+			t.Errorf("FindAffineEditScript(%+q, %+q, Graphemes) = %d, want %d", s1, s2, distance, want)
+		}
+		if err := script.Validate(s1, s2, nil, distance); err != nil {
+			t.Errorf("FindAffineEditScript(%+q, %+q, Graphemes): %v", s1, s2, err)
+		}
+	}
+}
+
+func TestAffinePrefersOneGap(t *testing.T) {
+	unit := testCosts[0].costs
+	tests := []struct {
+		s1, s2   string
+		gaps     AffineGaps
+		distance int
+		ops      string
+	}{
+		{"abcdef", "af", AffineGaps{3, 1}, 7, "MDDDDM"},
+		{"af", "abcdef", AffineGaps{3, 1}, 7, "MIIIIM"},
+		// Without an opening cost two short gaps are as cheap as one long one.
+		{"abcdef", "bcf", AffineGaps{0, 1}, 3, "DMMDDM"},
+		// Replacing is cheaper than opening a gap on each side.
+		{"abc", "axc", AffineGaps{3, 1}, 1, "MRM"},
+	}
+	for _, test := range tests {
+		distance, script := FindAffineEditScript(test.s1, test.s2, Runes, test.gaps, unit, quietLogger())
+		if distance != test.distance || script.String() != test.ops {
+			t.Errorf("FindAffineEditScript(%q, %q, %+v) = %d %s, want %d %s", test.s1, test.s2, test.gaps,
+				distance, script, test.distance, test.ops)
+		}
+	}
+}
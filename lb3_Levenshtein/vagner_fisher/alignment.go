@@ -0,0 +1,234 @@
+package vagner_fisher
+
+import (
+	"fmt"
+	"strings"
+
+	"lb3_Levenshtein/logger"
+)
+
+// AlignmentMode chooses which ends of the strings may be left out of the
+// alignment for free. s1 is the query and s2 the target everywhere except in
+// Local mode, where both strings are trimmed.
+type AlignmentMode int
+
+const (
+	Global AlignmentMode = iota
+	Prefix
+	Suffix
+	Infix
+	Local
+)
+
+const stop = 'S'
+
+// Alignment is the aligned region s1[SourceStart:SourceEnd] against
+// s2[TargetStart:TargetEnd] in symbols of Seg. Script indices are positions
+// in the whole strings. Score is only set by local alignment.
+type Alignment struct {
+	Mode        AlignmentMode
+	Seg         Segmentation
+	Distance    int
+	Score       int
+	SourceStart int
+	SourceEnd   int
+	TargetStart int
+	TargetEnd   int
+	Script      EditScript
+}
+
+func (mode AlignmentMode) String() string {
+	switch mode {
+	case Prefix:
+		return "prefix"
+	case Suffix:
+		return "suffix"
+	case Infix:
+		return "infix"
+	case Local:
+		return "local"
+	}
+	return "global"
+}
+
+// FindAlignment aligns s1 against s2 in the given mode. Prefix matches s1
+// against the cheapest prefix of s2, Suffix against a suffix and Infix against
+// any substring. Local mode scores every match as 1.
+func FindAlignment(s1, s2 string, seg Segmentation, mode AlignmentMode, costs CostModel, log Logger) Alignment {
+	log, costs = orDiscard(log), orUnit(costs)
+	if mode == Local {
+		return FindLocalAlignment(s1, s2, seg, 1, costs, log)
+	}
+
+	a, b := Split(s1, seg), Split(s2, seg)
+	n, m := len(a), len(b)
+	freeStart := mode == Suffix || mode == Infix
+	freeEnd := mode == Prefix || mode == Infix
+
+	dp := make([][]int, n+1)
+	ops := make([][]rune, n+1)
+	for i := range dp {
+		dp[i] = make([]int, m+1)
+		ops[i] = make([]rune, m+1)
+	}
+
+	log.LogMsg("Init", fmt.Sprintf("Aligning '%s' (%d) against '%s' (%d) in %s mode", s1, n, s2, m, mode),
+		logger.ColorCyan)
+
+	ops[0][0] = stop
+	for j := 1; j <= m; j++ {
+		if freeStart {
+			ops[0][j] = stop
+		} else {
+			dp[0][j] = dp[0][j-1] + costs.InsertCost(b[j-1])
+			ops[0][j] = Insert
+		}
+	}
+	for i := 1; i <= n; i++ {
+		dp[i][0] = dp[i-1][0] + costs.DeleteCost(a[i-1])
+		ops[i][0] = Delete
+	}
+
+	for i := 1; i <= n; i++ {
+		for j := 1; j <= m; j++ {
+			diagTotal := dp[i-1][j-1] + matchCost(costs, a[i-1])
+			if a[i-1] != b[j-1] {
+				diagTotal = dp[i-1][j-1] + costs.ReplaceCost(a[i-1], b[j-1])
+			}
+			ops[i][j], dp[i][j] = minOperation(
+				diagTotal,
+				dp[i][j-1]+costs.InsertCost(b[j-1]),
+				dp[i-1][j]+costs.DeleteCost(a[i-1]),
+			)
+			if ops[i][j] == Replace && a[i-1] == b[j-1] {
+				ops[i][j] = Match
+			}
+		}
+	}
+
+	log.LogCostMatrix("DP", dp, logger.ColorRed)
+	log.LogRuneMatrix("Ops", ops, logger.ColorBlue)
+
+	end := m
+	if freeEnd {
+		end = 0
+		for j := 1; j <= m; j++ {
+			if dp[n][j] < dp[n][end] {
+				end = j
+			}
+		}
+	}
+
+	alignment := traceAlignment(a, b, n, end, ops, costs)
+	alignment.Mode = mode
+	alignment.Seg = seg
+	alignment.Distance = dp[n][end]
+
+	log.LogMsg("Result", fmt.Sprintf("Distance %d, s1[%d:%d] against s2[%d:%d], Path: %s", alignment.Distance,
+		alignment.SourceStart, alignment.SourceEnd, alignment.TargetStart, alignment.TargetEnd, alignment.Script),
+		logger.ColorGreen)
+
+	return alignment
+}
+
+// FindLocalAlignment is Smith-Waterman alignment: a match scores match and
+// every other edit subtracts its cost. The best-scoring pair of substrings is
+// returned; Distance is the edit cost of the script between them.
+func FindLocalAlignment(s1, s2 string, seg Segmentation, match int, costs CostModel, log Logger) Alignment {
+	log, costs = orDiscard(log), orUnit(costs)
+	a, b := Split(s1, seg), Split(s2, seg)
+	n, m := len(a), len(b)
+
+	score := make([][]int, n+1)
+	ops := make([][]rune, n+1)
+	for i := range score {
+		score[i] = make([]int, m+1)
+		ops[i] = make([]rune, m+1)
+		for j := range ops[i] {
+			ops[i][j] = stop
+		}
+	}
+
+	log.LogMsg("Init", fmt.Sprintf("Local alignment of '%s' (%d) and '%s' (%d), match score %d", s1, n, s2, m, match),
+		logger.ColorCyan)
+
+	bestI, bestJ := 0, 0
+	for i := 1; i <= n; i++ {
+		for j := 1; j <= m; j++ {
+			diagTotal := score[i-1][j-1] + match
+			if a[i-1] != b[j-1] {
+				diagTotal = score[i-1][j-1] - costs.ReplaceCost(a[i-1], b[j-1])
+			}
+
+			// minOperation on negated scores keeps the usual tie order.
+			op, negated := minOperation(
+				-diagTotal,
+				-(score[i][j-1] - costs.InsertCost(b[j-1])),
+				-(score[i-1][j] - costs.DeleteCost(a[i-1])),
+			)
+			if op == Replace && a[i-1] == b[j-1] {
+				op = Match
+			}
+			if -negated > 0 {
+				score[i][j], ops[i][j] = -negated, op
+			}
+
+			if score[i][j] > score[bestI][bestJ] {
+				bestI, bestJ = i, j
+			}
+		}
+	}
+
+	log.LogCostMatrix("Scores", score, logger.ColorRed)
+	log.LogRuneMatrix("Ops", ops, logger.ColorBlue)
+
+	alignment := traceAlignment(a, b, bestI, bestJ, ops, costs)
+	alignment.Mode = Local
+	alignment.Seg = seg
+	alignment.Score = score[bestI][bestJ]
+	alignment.Distance = alignment.Script.Cost()
+
+	log.LogMsg("Result", fmt.Sprintf("Score %d, s1[%d:%d] against s2[%d:%d], Path: %s", alignment.Score,
+		alignment.SourceStart, alignment.SourceEnd, alignment.TargetStart, alignment.TargetEnd, alignment.Script),
+		logger.ColorGreen)
+
+	return alignment
+}
+
+func traceAlignment(a, b []string, i, j int, ops [][]rune, costs CostModel) Alignment {
+	alignment := Alignment{SourceEnd: i, TargetEnd: j}
+
+	var path []rune
+	for ops[i][j] != stop {
+		op := ops[i][j]
+		path = append(path, op)
+		switch op {
+		case Insert:
+			j--
+		case Delete:
+			i--
+		default:
+			i--
+			j--
+		}
+	}
+	alignment.SourceStart, alignment.TargetStart = i, j
+
+	for k := 0; k < len(path)/2; k++ {
+		path[k], path[len(path)-1-k] = path[len(path)-1-k], path[k]
+	}
+
+	alignment.Script = EditScript(scriptFromPath(a[i:], b[j:], string(path), costs))
+	for k := range alignment.Script {
+		alignment.Script[k].SourceIndex += i
+		alignment.Script[k].TargetIndex += j
+	}
+
+	return alignment
+}
+
+// Substrings returns the aligned regions of s1 and s2.
+func (al Alignment) Substrings(s1, s2 string) (string, string) {
+	a, b := Split(s1, al.Seg), Split(s2, al.Seg)
+	return strings.Join(a[al.SourceStart:al.SourceEnd], ""), strings.Join(b[al.TargetStart:al.TargetEnd], "")
+}
@@ -0,0 +1,61 @@
+package vagner_fisher
+
+import "testing"
+
+func TestAlignmentModes(t *testing.T) {
+	unit := NewCostTable(1, 1, 1, 1)
+	tests := []struct {
+		s1, s2         string
+		mode           AlignmentMode
+		distance       int
+		source, target [2]int
+		ops            string
+	}{
+		{"abc", "abcxyz", Global, 3, [2]int{0, 3}, [2]int{0, 6}, "MMMIII"},
+		{"abc", "abcxyz", Prefix, 0, [2]int{0, 3}, [2]int{0, 3}, "MMM"},
+		{"xyz", "abcxyz", Prefix, 3, [2]int{0, 3}, [2]int{0, 0}, "DDD"},
+		{"xyz", "abcxyz", Suffix, 0, [2]int{0, 3}, [2]int{3, 6}, "MMM"},
+		{"abc", "abcxyz", Suffix, 3, [2]int{0, 3}, [2]int{3, 6}, "RRR"},
+		{"bcd", "abxcdef", Prefix, 2, [2]int{0, 3}, [2]int{0, 5}, "IMIMM"},
+		{"bcd", "abxcdef", Infix, 1, [2]int{0, 3}, [2]int{2, 5}, "RMM"},
+		{"abc", "abdxx", Infix, 1, [2]int{0, 3}, [2]int{0, 2}, "MMD"},
+		{"", "abc", Suffix, 0, [2]int{0, 0}, [2]int{3, 3}, ""},
+		{"bcd", "abxcdef", Local, 0, [2]int{1, 3}, [2]int{3, 5}, "MM"},
+		{"xxabcyy", "zzabcww", Local, 0, [2]int{2, 5}, [2]int{2, 5}, "MMM"},
+		{"abc", "xyz", Local, 0, [2]int{0, 0}, [2]int{0, 0}, ""},
+	}
+	for _, test := range tests {
+		al := FindAlignment(test.s1, test.s2, Runes, test.mode, unit, quietLogger())
+		source, target := [2]int{al.SourceStart, al.SourceEnd}, [2]int{al.TargetStart, al.TargetEnd}
+		if al.Distance != test.distance || source != test.source || target != test.target || al.Script.String() != test.ops {
+			t.Errorf("FindAlignment(%q, %q, %s) = %d %v %v %s, want %d %v %v %s", test.s1, test.s2, test.mode,
+				al.Distance, source, target, al.Script, test.distance, test.source, test.target, test.ops)
+		}
+	}
+}
+
+func TestLocalAlignmentScore(t *testing.T) {
+	unit := NewCostTable(1, 1, 1, 1)
+	tests := []struct {
+		s1, s2     string
+		seg        Segmentation
+		match      int
+		score      int
+		ops        string
+		sub1, sub2 string
+	}{
+		// A cheap mismatch is worth bridging when matches score 2.
+		{"xxabcyy", "zzabdyy", Runes, 2, 7, "MMRMM", "abcyy", "abdyy"},
+		{"xxabcyy", "zzabdyy", Runes, 1, 3, "MMRMM", "abcyy", "abdyy"},
+		{"abxxcd", "abyycd", Runes, 1, 2, "MM", "ab", "ab"},
+		{"xe\u0301e\u0301y", "ze\u0301e\u0301w", Graphemes, 1, 2, "MM", "e\u0301e\u0301", "e\u0301e\u0301"},
+	}
+	for _, test := range tests {
+		al := FindLocalAlignment(test.s1, test.s2, test.seg, test.match, unit, quietLogger())
+		sub1, sub2 := al.Substrings(test.s1, test.s2)
+		if al.Score != test.score || al.Script.String() != test.ops || sub1 != test.sub1 || sub2 != test.sub2 {
+			t.Errorf("FindLocalAlignment(%+q, %+q, %d) = %d %s %+q %+q, want %d %s %+q %+q", test.s1, test.s2,
+				test.match, al.Score, al.Script, sub1, sub2, test.score, test.ops, test.sub1, test.sub2)
+		}
+	}
+}
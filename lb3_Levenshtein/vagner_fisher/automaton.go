@@ -0,0 +1,263 @@
+package vagner_fisher
+
+import (
+	"fmt"
+	"maps"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// LevenshteinAutomaton accepts the words within maxDistance of the query under
+// unit costs. Its states are DP rows of the query against the symbols read so
+// far, with every value above maxDistance clipped to maxDistance+1. Only
+// finitely many such rows exist, so the DFA is built lazily as words are fed
+// through it and its transitions are cached.
+//
+// With OptimalStringAlignment a state also keeps the previous row and the
+// last symbol, as far as it occurs in the query.
+type LevenshteinAutomaton struct {
+	query       []string
+	maxDistance int
+	variant     Transposition
+	inQuery     map[string]bool
+	states      []automatonState
+	index       map[string]int
+}
+
+type automatonState struct {
+	row         []int
+	prevRow     []int
+	last        string
+	transitions map[string]int
+}
+
+type DictionaryMatch struct {
+	Word     string
+	Distance int
+}
+
+func NewLevenshteinAutomaton(query string, maxDistance int, variant Transposition) (*LevenshteinAutomaton, error) {
+	if maxDistance < 0 {
+		return nil, fmt.Errorf("negative maximum distance %d", maxDistance)
+	}
+	if variant == UnrestrictedTransposition {
+		return nil, fmt.Errorf("unrestricted transpositions are not supported by the automaton")
+	}
+
+	la := &LevenshteinAutomaton{
+		query:       Split(query, Runes),
+		maxDistance: maxDistance,
+		variant:     variant,
+		inQuery:     make(map[string]bool),
+		index:       make(map[string]int),
+	}
+	for _, symbol := range la.query {
+		la.inQuery[symbol] = true
+	}
+
+	row := make([]int, len(la.query)+1)
+	for i := range row {
+		row[i] = min(i, maxDistance+1)
+	}
+	la.addState(row, nil, "")
+
+	return la, nil
+}
+
+func (la *LevenshteinAutomaton) addState(row, prevRow []int, last string) int {
+	var key strings.Builder
+	for _, rows := range [][]int{row, prevRow} {
+		for _, value := range rows {
+			key.WriteString(strconv.Itoa(value))
+			key.WriteByte(',')
+		}
+		key.WriteByte('|')
+	}
+	key.WriteString(last)
+
+	if id, ok := la.index[key.String()]; ok {
+		return id
+	}
+	la.states = append(la.states, automatonState{row: row, prevRow: prevRow, last: last, transitions: make(map[string]int)})
+	la.index[key.String()] = len(la.states) - 1
+	return len(la.states) - 1
+}
+
+func (la *LevenshteinAutomaton) Start() int {
+	return 0
+}
+
+func (la *LevenshteinAutomaton) Step(state int, symbol string) int {
+	if next, ok := la.states[state].transitions[symbol]; ok {
+		return next
+	}
+
+	s := la.states[state]
+	limit := la.maxDistance + 1
+	row := make([]int, len(s.row))
+	row[0] = min(s.row[0]+1, limit)
+	for i := 1; i < len(row); i++ {
+		diagTotal := s.row[i-1]
+		if la.query[i-1] != symbol {
+			diagTotal++
+		}
+		row[i] = min(diagTotal, row[i-1]+1, s.row[i]+1)
+		if s.prevRow != nil && i > 1 && la.query[i-1] == s.last && la.query[i-2] == symbol {
+			row[i] = min(row[i], s.prevRow[i-2]+1)
+		}
+		row[i] = min(row[i], limit)
+	}
+
+	var prevRow []int
+	last := ""
+	if la.variant == OptimalStringAlignment {
+		prevRow = s.row
+		if la.inQuery[symbol] {
+			last = symbol
+		}
+	}
+
+	next := la.addState(row, prevRow, last)
+	la.states[state].transitions[symbol] = next
+	return next
+}
+
+// CanMatch reports whether some continuation of the symbols read so far can
+// still be accepted.
+func (la *LevenshteinAutomaton) CanMatch(state int) bool {
+	return slices.Min(la.states[state].row) <= la.maxDistance
+}
+
+func (la *LevenshteinAutomaton) Distance(state int) (int, bool) {
+	row := la.states[state].row
+	distance := row[len(row)-1]
+	return distance, distance <= la.maxDistance
+}
+
+func (la *LevenshteinAutomaton) Match(word string) (int, bool) {
+	state := la.Start()
+	for _, symbol := range Split(word, Runes) {
+		state = la.Step(state, symbol)
+		if !la.CanMatch(state) {
+			return 0, false
+		}
+	}
+	return la.Distance(state)
+}
+
+// SearchSorted returns the matching words of a dictionary sorted in byte
+// order. Words sharing a prefix with the previous word reuse its states, and
+// once a prefix can no longer match every word starting with it is skipped by
+// binary search.
+func (la *LevenshteinAutomaton) SearchSorted(words []string) []DictionaryMatch {
+	var matches []DictionaryMatch
+	stack := []int{la.Start()}
+	var prev []string
+
+	for idx := 0; idx < len(words); {
+		word := Split(words[idx], Runes)
+		common := 0
+		for common < min(len(prev), len(word), len(stack)-1) && prev[common] == word[common] {
+			common++
+		}
+		stack = stack[:common+1]
+		prev = word
+
+		dead := false
+		for k := common; k < len(word); k++ {
+			state := la.Step(stack[k], word[k])
+			stack = append(stack, state)
+			if !la.CanMatch(state) {
+				prefix := strings.Join(word[:k+1], "")
+				idx += 1 + sort.Search(len(words)-idx-1, func(x int) bool {
+					return !strings.HasPrefix(words[idx+1+x], prefix)
+				})
+				dead = true
+				break
+			}
+		}
+		if dead {
+			continue
+		}
+
+		if distance, ok := la.Distance(stack[len(word)]); ok {
+			matches = append(matches, DictionaryMatch{words[idx], distance})
+		}
+		idx++
+	}
+
+	return matches
+}
+
+type trieNode struct {
+	children map[string]*trieNode
+	terminal bool
+}
+
+// Trie stores a dictionary by symbols for SearchTrie.
+type Trie struct {
+	root trieNode
+	size int
+}
+
+func NewTrie(words []string) *Trie {
+	t := &Trie{}
+	for _, word := range words {
+		t.Insert(word)
+	}
+	return t
+}
+
+func (t *Trie) Insert(word string) {
+	cur := &t.root
+	for _, symbol := range Split(word, Runes) {
+		if cur.children == nil {
+			cur.children = make(map[string]*trieNode)
+		}
+		child, ok := cur.children[symbol]
+		if !ok {
+			child = &trieNode{}
+			cur.children[symbol] = child
+		}
+		cur = child
+	}
+	if !cur.terminal {
+		cur.terminal = true
+		t.size++
+	}
+}
+
+func (t *Trie) Len() int {
+	return t.size
+}
+
+// SearchTrie walks the trie and the automaton together, pruning every
+// subtree whose prefix can no longer match. Words are returned in byte order.
+func (la *LevenshteinAutomaton) SearchTrie(t *Trie) []DictionaryMatch {
+	var matches []DictionaryMatch
+	var prefix []string
+
+	var walk func(n *trieNode, state int)
+	walk = func(n *trieNode, state int) {
+		if n.terminal {
+			if distance, ok := la.Distance(state); ok {
+				matches = append(matches, DictionaryMatch{strings.Join(prefix, ""), distance})
+			}
+		}
+
+		for _, symbol := range slices.Sorted(maps.Keys(n.children)) {
+			next := la.Step(state, symbol)
+			if !la.CanMatch(next) {
+				continue
+			}
+			prefix = append(prefix, symbol)
+			walk(n.children[symbol], next)
+			prefix = prefix[:len(prefix)-1]
+		}
+	}
+
+	walk(&t.root, la.Start())
+	return matches
+}
@@ -0,0 +1,103 @@
+package vagner_fisher
+
+import (
+	"math/rand"
+	"slices"
+	"testing"
+)
+
+func randomWord(r *rand.Rand, alphabet string, maxLen int) string {
+	symbols := []rune(alphabet)
+	word := make([]rune, r.Intn(maxLen+1))
+	for k := range word {
+		word[k] = symbols[r.Intn(len(symbols))]
+	}
+	return string(word)
+}
+
+func TestAutomatonMatchesDP(t *testing.T) {
+	r := rand.New(rand.NewSource(4))
+	unit := NewCostTable(1, 1, 1, 1)
+	var words []string
+	for range 400 {
+		words = append(words, randomWord(r, "abcd", 7))
+	}
+	slices.Sort(words)
+	words = slices.Compact(words)
+	trie := NewTrie(words)
+
+	for _, variant := range []Transposition{NoTransposition, OptimalStringAlignment} {
+		for maxDistance := 1; maxDistance <= 3; maxDistance++ {
+			for range 50 {
+				query := randomWord(r, "abcde", 6)
+				la, err := NewLevenshteinAutomaton(query, maxDistance, variant)
+				if err != nil {
+					t.Fatal(err)
+				}
+
+				var want []DictionaryMatch
+				for _, word := range words {
+					distance, _ := FindDamerauLevenshteinDistance(query, word, Runes, variant, unit, quietLogger())
+					if distance <= maxDistance {
+						want = append(want, DictionaryMatch{word, distance})
+					}
+					if got, ok := la.Match(word); ok != (distance <= maxDistance) || ok && got != distance {
+						t.Errorf("%d: automaton(%q, %d).Match(%q) = %d, %v, want distance %d", variant, query,
+							maxDistance, word, got, ok, distance)
+					}
+				}
+
+				if got := la.SearchSorted(words); !slices.Equal(got, want) {
+					t.Errorf("%d: SearchSorted(%q, %d) = %v, want %v", variant, query, maxDistance, got, want)
+				}
+				if got := la.SearchTrie(trie); !slices.Equal(got, want) {
+					t.Errorf("%d: SearchTrie(%q, %d) = %v, want %v", variant, query, maxDistance, got, want)
+				}
+			}
+		}
+	}
+}
+
+func TestAutomatonDictionary(t *testing.T) {
+	words := []string{"help", "hello", "hell", "helm", "held", "shell", "world", "word", "привет"}
+	slices.Sort(words)
+	trie := NewTrie(words)
+	tests := []struct {
+		query       string
+		maxDistance int
+		variant     Transposition
+		want        []DictionaryMatch
+	}{
+		{"helo", 1, NoTransposition, []DictionaryMatch{{"held", 1}, {"hell", 1}, {"hello", 1}, {"helm", 1}, {"help", 1}}},
+		{"hlelo", 1, NoTransposition, nil},
+		{"hlelo", 1, OptimalStringAlignment, []DictionaryMatch{{"hello", 1}}},
+		{"wrold", 2, NoTransposition, []DictionaryMatch{{"word", 2}, {"world", 2}}},
+		{"wrold", 1, OptimalStringAlignment, []DictionaryMatch{{"world", 1}}},
+		{"привед", 1, NoTransposition, []DictionaryMatch{{"привет", 1}}},
+		{"shell", 0, NoTransposition, []DictionaryMatch{{"shell", 0}}},
+	}
+	for _, test := range tests {
+		la, err := NewLevenshteinAutomaton(test.query, test.maxDistance, test.variant)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := la.SearchSorted(words); !slices.Equal(got, test.want) {
+			t.Errorf("SearchSorted(%q, %d, %d) = %v, want %v", test.query, test.maxDistance, test.variant, got, test.want)
+		}
+		if got := la.SearchTrie(trie); !slices.Equal(got, test.want) {
+			t.Errorf("SearchTrie(%q, %d, %d) = %v, want %v", test.query, test.maxDistance, test.variant, got, test.want)
+		}
+	}
+	if trie.Len() != len(words) {
+		t.Errorf("Trie.Len = %d, want %d", trie.Len(), len(words))
+	}
+}
+
+func TestAutomatonRejects(t *testing.T) {
+	if _, err := NewLevenshteinAutomaton("abc", -1, NoTransposition); err == nil {
+		t.Errorf("NewLevenshteinAutomaton accepts a negative distance")
+	}
+	if _, err := NewLevenshteinAutomaton("abc", 1, UnrestrictedTransposition); err == nil {
+		t.Errorf("NewLevenshteinAutomaton accepts unrestricted transpositions")
+	}
+}
@@ -0,0 +1,103 @@
+package vagner_fisher
+
+import (
+	"fmt"
+	"math"
+
+	"lb3_Levenshtein/logger"
+)
+
+const unreachable = math.MaxInt / 2
+
+// FindLevenshteinDistanceBounded reports whether the distance is at most k and
+// returns it only in that case. Cells further than k/minIndel from the main
+// diagonal can never lie on a path of cost <= k, so only that band is computed.
+func FindLevenshteinDistanceBounded(s1, s2 string, seg Segmentation, k int, costs CostModel, log Logger) (int, bool) {
+	log, costs = orDiscard(log), orUnit(costs)
+	a, b := Split(s1, seg), Split(s2, seg)
+	n, m := len(a), len(b)
+
+	if k < 0 {
+		return 0, false
+	}
+
+	minIndel := unreachable
+	for _, symbol := range a {
+		minIndel = min(minIndel, costs.DeleteCost(symbol))
+	}
+	for _, symbol := range b {
+		minIndel = min(minIndel, costs.InsertCost(symbol))
+	}
+
+	band := max(n, m)
+	if minIndel > 0 {
+		band = min(band, k/minIndel)
+	}
+
+	log.LogMsg("Init", fmt.Sprintf("Checking distance <= %d between strings of length %d and %d, band %d",
+		k, n, m, band),
+		logger.ColorCyan)
+
+	if n-m > band || m-n > band {
+		log.LogMsg("Result", fmt.Sprintf("Length difference %d is outside the band", n-m),
+			logger.ColorRed)
+		return 0, false
+	}
+
+	prev := make([]int, m+1)
+	cur := make([]int, m+1)
+	for j := range prev {
+		prev[j], cur[j] = unreachable, unreachable
+	}
+
+	prev[0] = 0
+	for j := 1; j <= min(m, band); j++ {
+		prev[j] = prev[j-1] + costs.InsertCost(b[j-1])
+	}
+
+	for i := 1; i <= n; i++ {
+		from, to := max(1, i-band), min(m, i+band)
+
+		cur[0] = unreachable
+		if i <= band {
+			cur[0] = prev[0] + costs.DeleteCost(a[i-1])
+		}
+		if from > 1 {
+			cur[from-1] = unreachable
+		}
+
+		rowMin := cur[0]
+		for j := from; j <= to; j++ {
+			diagTotal := prev[j-1]
+			if a[i-1] != b[j-1] {
+				diagTotal += costs.ReplaceCost(a[i-1], b[j-1])
+			}
+			_, cur[j] = minOperation(
+				diagTotal,
+				cur[j-1]+costs.InsertCost(b[j-1]),
+				prev[j]+costs.DeleteCost(a[i-1]),
+			)
+			cur[j] = min(cur[j], unreachable)
+			rowMin = min(rowMin, cur[j])
+		}
+		if to < m {
+			cur[to+1] = unreachable
+		}
+
+		if rowMin > k {
+			log.LogMsg("Result", fmt.Sprintf("Every cell of row %d exceeds %d, stopping", i, k),
+				logger.ColorRed)
+			return 0, false
+		}
+
+		prev, cur = cur, prev
+	}
+
+	if prev[m] > k {
+		log.LogMsg("Result", fmt.Sprintf("Final distance %d exceeds %d", prev[m], k), logger.ColorRed)
+		return 0, false
+	}
+
+	log.LogMsg("Result", fmt.Sprintf("Final distance: %d", prev[m]), logger.ColorGreen)
+	return prev[m], true
+}
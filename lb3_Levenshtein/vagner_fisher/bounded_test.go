@@ -0,0 +1,60 @@
+package vagner_fisher
+
+import "testing"
+
+func TestBoundedAtThreshold(t *testing.T) {
+	for _, cc := range testCosts {
+		for _, pair := range testPairs {
+			s1, s2 := pair[0], pair[1]
+			d, _ := FindLevenshteinDistance(s1, s2, cc.costs, debugLogger())
+
+			if got, ok := FindLevenshteinDistanceBounded(s1, s2, Runes, d, cc.costs, quietLogger()); !ok || got != d {
+				t.Errorf("%s: Bounded(%q, %q, %d) = %d, %v, want %d, true", cc.name, s1, s2, d, got, ok, d)
+			}
+			if d == 0 {
+				continue
+			}
+			if _, ok := FindLevenshteinDistanceBounded(s1, s2, Runes, d-1, cc.costs, quietLogger()); ok {
+				t.Errorf("%s: Bounded(%q, %q, %d) reports ok for distance %d", cc.name, s1, s2, d-1, d)
+			}
+		}
+	}
+}
+
+func TestBoundedEdgeCases(t *testing.T) {
+	unit := testCosts[0]
+	tests := []struct {
+		s1, s2   string
+		k        int
+		distance int
+		ok       bool
+	}{
+		{"abc", "abc", 0, 0, true},
+		{"abc", "abd", 0, 0, false},
+		{"abc", "abc", -1, 0, false},
+		// The length difference alone is outside the band.
+		{"abc", "abcdefgh", 4, 0, false},
+		{"abc", "abcdefgh", 5, 5, true},
+		// A large bound makes the band wider than the strings.
+		{"kitten", "sitting", 100, 3, true},
+	}
+	for _, test := range tests {
+		distance, ok := FindLevenshteinDistanceBounded(test.s1, test.s2, Runes, test.k, unit.costs, quietLogger())
+		if distance != test.distance || ok != test.ok {
+			t.Errorf("Bounded(%q, %q, %d) = %d, %v, want %d, %v", test.s1, test.s2, test.k, distance, ok,
+				test.distance, test.ok)
+		}
+	}
+}
+
+func TestBoundedOnGraphemes(t *testing.T) {
+	for _, cc := range testCosts {
+		for _, pair := range graphemePairs {
+			s1, s2 := pair[0], pair[1]
+			d, _ := FindLevenshteinDistanceSegmented(s1, s2, Graphemes, cc.costs, debugLogger())
+			if got, ok := FindLevenshteinDistanceBounded(s1, s2, Graphemes, d, cc.costs, quietLogger()); !ok || got != d {
+				t.Errorf("%s: Bounded(%+q, %+q, Graphemes, %d) = %d, %v, want %d, true", cc.name, s1, s2, d, got, ok, d)
+			}
+		}
+	}
+}
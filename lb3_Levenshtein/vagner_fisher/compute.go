@@ -0,0 +1,215 @@
+package vagner_fisher
+
+import (
+	"errors"
+	"fmt"
+)
+
+var (
+	ErrNilCosts      = errors.New("nil cost model")
+	ErrNegativeCost  = errors.New("negative cost")
+	ErrCostOverflow  = errors.New("cost overflows the distance")
+	ErrInvalidOption = errors.New("invalid option")
+	// ErrInexactTransposition rejects unrestricted transpositions that are
+	// cheap enough for Lowrance-Wagner to miss the optimal script.
+	ErrInexactTransposition = errors.New("transposition too cheap for unrestricted transpositions")
+)
+
+// CostError reports the edit whose cost failed validation. It wraps
+// ErrNegativeCost, ErrCostOverflow or ErrInexactTransposition.
+type CostError struct {
+	Op   rune
+	From string
+	To   string
+	Cost int
+	Err  error
+}
+
+func (e *CostError) Error() string {
+	return fmt.Sprintf("%s: %c %q -> %q costs %d", e.Err, e.Op, e.From, e.To, e.Cost)
+}
+
+func (e *CostError) Unwrap() error {
+	return e.Err
+}
+
+// OptionError reports an option with an unusable value. It wraps
+// ErrInvalidOption or ErrNilCosts.
+type OptionError struct {
+	Option string
+	Value  any
+	Err    error
+}
+
+func (e *OptionError) Error() string {
+	return fmt.Sprintf("%s: %s %v", e.Err, e.Option, e.Value)
+}
+
+func (e *OptionError) Unwrap() error {
+	return e.Err
+}
+
+type options struct {
+	seg     Segmentation
+	variant Transposition
+	costs   CostModel
+	log     Logger
+	errs    []error
+}
+
+type Option func(*options)
+
+// WithCosts sets the cost model. Without it every edit costs 1.
+func WithCosts(costs CostModel) Option {
+	return func(o *options) {
+		if costs == nil {
+			o.errs = append(o.errs, &OptionError{"costs", costs, ErrNilCosts})
+		}
+		o.costs = costs
+	}
+}
+
+// WithOperationCosts is WithCosts for a ClassicCosts model. A nil specRunes
+// means no special runes.
+func WithOperationCosts(opCosts *OperationCosts, specRunes *SpecialRunes) Option {
+	return func(o *options) {
+		if opCosts == nil {
+			o.errs = append(o.errs, &OptionError{"operation costs", opCosts, ErrNilCosts})
+			return
+		}
+		o.costs = NewClassicCosts(opCosts, specRunes)
+	}
+}
+
+func WithSegmentation(seg Segmentation) Option {
+	return func(o *options) {
+		if seg != Runes && seg != Graphemes {
+			o.errs = append(o.errs, &OptionError{"segmentation", seg, ErrInvalidOption})
+		}
+		o.seg = seg
+	}
+}
+
+func WithTransposition(variant Transposition) Option {
+	return func(o *options) {
+		if variant < NoTransposition || variant > UnrestrictedTransposition {
+			o.errs = append(o.errs, &OptionError{"transposition", variant, ErrInvalidOption})
+		}
+		o.variant = variant
+	}
+}
+
+// WithLogger sets the debug logger. A nil logger disables logging.
+func WithLogger(log Logger) Option {
+	return func(o *options) {
+		o.log = log
+	}
+}
+
+type Result struct {
+	Distance int
+	Script   EditScript
+}
+
+// Compute is FindEditScript with its configuration checked first: invalid
+// options and every cost the strings can incur are validated, and the first
+// problem is returned instead of a distance. Costs must be non-negative and
+// small enough that no script over the strings can overflow.
+func Compute(s1, s2 string, opts ...Option) (Result, error) {
+	o := options{seg: Runes, costs: UnitCosts[string]{}}
+	for _, opt := range opts {
+		opt(&o)
+	}
+	if len(o.errs) > 0 {
+		return Result{}, o.errs[0]
+	}
+
+	if err := ValidateCosts(s1, s2, o.seg, o.variant, o.costs); err != nil {
+		return Result{}, err
+	}
+
+	distance, script := FindEditScript(s1, s2, o.seg, o.variant, o.costs, o.log)
+	return Result{distance, script}, nil
+}
+
+// ValidateCosts checks the costs of s1 and s2 the way Compute does, for
+// callers that pick the algorithm themselves. Nil costs are unit costs.
+func ValidateCosts(s1, s2 string, seg Segmentation, variant Transposition, costs CostModel) error {
+	return validateCosts(Split(s1, seg), Split(s2, seg), variant, orUnit(costs))
+}
+
+// validateCosts asks the cost model about every distinct symbol and pair of
+// symbols of a and b. Any script has at most len(a)+len(b) edits, so costs up
+// to unreachable divided by that many edits cannot overflow. Unrestricted
+// transpositions must also satisfy 2*Transpose >= Insert+Delete for the most
+// expensive insert and delete, see FindDamerauLevenshteinDistance.
+func validateCosts(a, b []string, variant Transposition, costs CostModel) error {
+	limit := unreachable / (len(a) + len(b) + 1)
+	check := func(op rune, from, to string, cost int) error {
+		switch {
+		case cost < 0:
+			return &CostError{op, from, to, cost, ErrNegativeCost}
+		case cost > limit:
+			return &CostError{op, from, to, cost, ErrCostOverflow}
+		}
+		return nil
+	}
+
+	source, target := distinct(a), distinct(b)
+	maxInsert, maxDelete := 0, 0
+	for _, y := range target {
+		if err := check(Insert, "", y, costs.InsertCost(y)); err != nil {
+			return err
+		}
+		maxInsert = max(maxInsert, costs.InsertCost(y))
+	}
+	for _, x := range source {
+		if err := check(Delete, x, "", costs.DeleteCost(x)); err != nil {
+			return err
+		}
+		maxDelete = max(maxDelete, costs.DeleteCost(x))
+	}
+
+	for _, x := range source {
+		for _, y := range target {
+			var err error
+			if x == y {
+				err = check(Match, x, y, matchCost(costs, x))
+			} else {
+				err = check(Replace, x, y, costs.ReplaceCost(x, y))
+			}
+			if err != nil {
+				return err
+			}
+		}
+		if variant == NoTransposition {
+			continue
+		}
+		for _, y := range source {
+			if x == y {
+				continue
+			}
+			cost := transposeCost(costs, x, y)
+			if err := check(Transpose, x, y, cost); err != nil {
+				return err
+			}
+			if variant == UnrestrictedTransposition && 2*cost < maxInsert+maxDelete {
+				return &CostError{Transpose, x, y, cost, ErrInexactTransposition}
+			}
+		}
+	}
+
+	return nil
+}
+
+func distinct(symbols []string) []string {
+	seen := make(map[string]bool)
+	var unique []string
+	for _, symbol := range symbols {
+		if !seen[symbol] {
+			seen[symbol] = true
+			unique = append(unique, symbol)
+		}
+	}
+	return unique
+}
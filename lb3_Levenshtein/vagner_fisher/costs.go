@@ -0,0 +1,305 @@
+package vagner_fisher
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// Costs prices single-symbol edits. Equal symbols match for free unless the
+// model implements MatchCost, so ReplaceCost is only asked about distinct
+// symbols.
+type Costs[T comparable] interface {
+	ReplaceCost(a, b T) int
+	InsertCost(b T) int
+	DeleteCost(a T) int
+}
+
+// CostModel prices edits of string symbols, runes or grapheme clusters.
+type CostModel = Costs[string]
+
+// UnitCosts charges 1 for every replace, insert, delete and transposition.
+type UnitCosts[T comparable] struct{}
+
+func (UnitCosts[T]) ReplaceCost(a, b T) int   { return 1 }
+func (UnitCosts[T]) InsertCost(b T) int       { return 1 }
+func (UnitCosts[T]) DeleteCost(a T) int       { return 1 }
+func (UnitCosts[T]) TransposeCost(a, b T) int { return 1 }
+func (UnitCosts[T]) Unit() bool               { return true }
+
+// TranspositionCosts is an optional extension of CostModel. Models without it
+// price a swap of ab into ba as two replacements.
+type TranspositionCosts interface {
+	TransposeCost(a, b string) int
+}
+
+// UnitCost is an optional extension of Costs for models that can tell without
+// being asked about every pair of symbols that each insert, delete and replace
+// costs 1 and matches are free. Such models use the bit-parallel Myers
+// algorithm instead of the DP.
+type UnitCost interface {
+	Unit() bool
+}
+
+// MatchCost is an optional extension of CostModel for models that charge for
+// matches, such as negated similarity scores.
+type MatchCost interface {
+	MatchCost(a string) int
+}
+
+type specialRuneModel interface {
+	isSpecialReplace(a string) bool
+	isSpecialInsert(b string) bool
+}
+
+type ClassicCosts struct {
+	Costs OperationCosts
+	Runes SpecialRunes
+}
+
+// NewClassicCosts copies the costs and special runes. Nil costs mean every
+// edit costs 1 and nil special runes that there are none.
+func NewClassicCosts(opCosts *OperationCosts, specRunes *SpecialRunes) *ClassicCosts {
+	if opCosts == nil {
+		opCosts = &OperationCosts{Replace: 1, Insert: 1, Delete: 1, SpecialReplace: 1, SpecialInsert: 1}
+	}
+	if specRunes == nil {
+		noSpecial := *opCosts
+		noSpecial.SpecialReplace, noSpecial.SpecialInsert = opCosts.Replace, opCosts.Insert
+		opCosts, specRunes = &noSpecial, &SpecialRunes{}
+	}
+	return &ClassicCosts{
+		Costs: *opCosts,
+		Runes: *specRunes,
+	}
+}
+
+func (c *ClassicCosts) isSpecialReplace(a string) bool {
+	return a == string(c.Runes.Replace)
+}
+
+func (c *ClassicCosts) isSpecialInsert(b string) bool {
+	return b == string(c.Runes.Insert)
+}
+
+func (c *ClassicCosts) ReplaceCost(a, b string) int {
+	if c.isSpecialReplace(a) {
+		return c.Costs.SpecialReplace
+	}
+	return c.Costs.Replace
+}
+
+func (c *ClassicCosts) InsertCost(b string) int {
+	if c.isSpecialInsert(b) {
+		return c.Costs.SpecialInsert
+	}
+	return c.Costs.Insert
+}
+
+func (c *ClassicCosts) DeleteCost(a string) int {
+	return c.Costs.Delete
+}
+
+func (c *ClassicCosts) TransposeCost(a, b string) int {
+	if c.Costs.Transpose == nil {
+		return c.Costs.Replace
+	}
+	return *c.Costs.Transpose
+}
+
+// Unit treats a zero special rune as no special rune.
+func (c *ClassicCosts) Unit() bool {
+	return c.Costs.Replace == 1 && c.Costs.Insert == 1 && c.Costs.Delete == 1 &&
+		(c.Runes.Replace == 0 || c.Costs.SpecialReplace == 1) &&
+		(c.Runes.Insert == 0 || c.Costs.SpecialInsert == 1)
+}
+
+func (c *ClassicCosts) String() string {
+	return fmt.Sprintf("Replace: %d, Insert: %d, Delete: %d, Transpose: %d, SpecialReplace: %d (%c), SpecialInsert: %d (%c)",
+		c.Costs.Replace, c.Costs.Insert, c.Costs.Delete, c.TransposeCost("", ""),
+		c.Costs.SpecialReplace, c.Runes.Replace, c.Costs.SpecialInsert, c.Runes.Insert)
+}
+
+// CostTable is a CostModel read from a text file with one rule per line:
+//
+//	default replace|insert|delete|transpose <cost>
+//	replace <a> <b> <cost>
+//	insert <b> <cost>
+//	delete <a> <cost>
+//	transpose <a> <b> <cost>
+//
+// Symbols may be written as U+XXXX, lines starting with '#' are comments.
+type CostTable struct {
+	DefaultReplace   int
+	DefaultInsert    int
+	DefaultDelete    int
+	DefaultTranspose int
+	Replace          map[[2]string]int
+	Insert           map[string]int
+	Delete           map[string]int
+	Transpose        map[[2]string]int
+}
+
+func NewCostTable(replace, insert, delete, transpose int) *CostTable {
+	return &CostTable{
+		DefaultReplace:   replace,
+		DefaultInsert:    insert,
+		DefaultDelete:    delete,
+		DefaultTranspose: transpose,
+		Replace:          make(map[[2]string]int),
+		Insert:           make(map[string]int),
+		Delete:           make(map[string]int),
+		Transpose:        make(map[[2]string]int),
+	}
+}
+
+func (t *CostTable) ReplaceCost(a, b string) int {
+	if cost, ok := t.Replace[[2]string{a, b}]; ok {
+		return cost
+	}
+	return t.DefaultReplace
+}
+
+func (t *CostTable) InsertCost(b string) int {
+	if cost, ok := t.Insert[b]; ok {
+		return cost
+	}
+	return t.DefaultInsert
+}
+
+func (t *CostTable) DeleteCost(a string) int {
+	if cost, ok := t.Delete[a]; ok {
+		return cost
+	}
+	return t.DefaultDelete
+}
+
+func (t *CostTable) TransposeCost(a, b string) int {
+	if cost, ok := t.Transpose[[2]string{a, b}]; ok {
+		return cost
+	}
+	return t.DefaultTranspose
+}
+
+func (t *CostTable) Unit() bool {
+	return t.DefaultReplace == 1 && t.DefaultInsert == 1 && t.DefaultDelete == 1 &&
+		len(t.Replace) == 0 && len(t.Insert) == 0 && len(t.Delete) == 0
+}
+
+func (t *CostTable) String() string {
+	return fmt.Sprintf("Replace: %d, Insert: %d, Delete: %d, Transpose: %d, %d symbol rules",
+		t.DefaultReplace, t.DefaultInsert, t.DefaultDelete, t.DefaultTranspose,
+		len(t.Replace)+len(t.Insert)+len(t.Delete)+len(t.Transpose))
+}
+
+func LoadCostTable(path string) (*CostTable, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	table, err := ParseCostTable(file)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return table, nil
+}
+
+func ParseCostTable(r io.Reader) (*CostTable, error) {
+	table := NewCostTable(1, 1, 1, 1)
+	scanner := bufio.NewScanner(r)
+
+	for line := 1; scanner.Scan(); line++ {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) == 0 || strings.HasPrefix(fields[0], "#") {
+			continue
+		}
+
+		if err := table.addRule(fields); err != nil {
+			return nil, fmt.Errorf("line %d: %w", line, err)
+		}
+	}
+
+	return table, scanner.Err()
+}
+
+func (t *CostTable) addRule(fields []string) error {
+	arity := map[string]int{"default": 3, "replace": 4, "insert": 3, "delete": 3, "transpose": 4}
+	expected, ok := arity[fields[0]]
+	if !ok {
+		return fmt.Errorf("unknown rule %q", fields[0])
+	}
+	if len(fields) != expected {
+		return fmt.Errorf("rule %q expects %d fields, got %d", fields[0], expected, len(fields))
+	}
+
+	cost, err := strconv.Atoi(fields[len(fields)-1])
+	if err != nil {
+		return fmt.Errorf("invalid cost: %w", err)
+	}
+
+	symbols := make([]string, 0, 2)
+	for _, field := range fields[1 : len(fields)-1] {
+		symbol, err := parseSymbol(field)
+		if err != nil {
+			return err
+		}
+		symbols = append(symbols, symbol)
+	}
+
+	switch fields[0] {
+	case "default":
+		switch fields[1] {
+		case "replace":
+			t.DefaultReplace = cost
+		case "insert":
+			t.DefaultInsert = cost
+		case "delete":
+			t.DefaultDelete = cost
+		case "transpose":
+			t.DefaultTranspose = cost
+		default:
+			return fmt.Errorf("unknown default %q", fields[1])
+		}
+	case "replace":
+		t.Replace[[2]string{symbols[0], symbols[1]}] = cost
+	case "insert":
+		t.Insert[symbols[0]] = cost
+	case "delete":
+		t.Delete[symbols[0]] = cost
+	case "transpose":
+		t.Transpose[[2]string{symbols[0], symbols[1]}] = cost
+	}
+
+	return nil
+}
+
+func parseSymbol(field string) (string, error) {
+	if !strings.HasPrefix(field, "U+") || len(field) == 2 {
+		return field, nil
+	}
+
+	code, err := strconv.ParseUint(field[2:], 16, 32)
+	if err != nil {
+		return "", fmt.Errorf("invalid code point %q", field)
+	}
+	return string(rune(code)), nil
+}
+
+func matchCost[T comparable](costs Costs[T], a T) int {
+	if mc, ok := any(costs).(interface{ MatchCost(a T) int }); ok {
+		return mc.MatchCost(a)
+	}
+	return 0
+}
+
+func transposeCost[T comparable](costs Costs[T], a, b T) int {
+	if tc, ok := any(costs).(interface{ TransposeCost(a, b T) int }); ok {
+		return tc.TransposeCost(a, b)
+	}
+	return costs.ReplaceCost(a, b) + costs.ReplaceCost(b, a)
+}
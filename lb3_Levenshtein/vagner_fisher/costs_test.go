@@ -0,0 +1,56 @@
+package vagner_fisher
+
+import "testing"
+
+func TestClassicTransposeCost(t *testing.T) {
+	zero, three := 0, 3
+	tests := []struct {
+		name      string
+		transpose *int
+		want      int
+		distance  int
+	}{
+		{"unset", nil, 2, 2},
+		{"free", &zero, 0, 0},
+		{"explicit", &three, 3, 3},
+	}
+	for _, test := range tests {
+		costs := NewClassicCosts(&OperationCosts{Replace: 2, Insert: 2, Delete: 2, Transpose: test.transpose}, &SpecialRunes{})
+		if got := costs.TransposeCost("a", "b"); got != test.want {
+			t.Errorf("%s: TransposeCost = %d, want %d", test.name, got, test.want)
+		}
+		if got, _ := FindEditScript("xaby", "xbay", Runes, OptimalStringAlignment, costs, debugLogger()); got != test.distance {
+			t.Errorf("%s: OSA distance = %d, want %d", test.name, got, test.distance)
+		}
+	}
+}
+
+func TestNilCosts(t *testing.T) {
+	if got, path := FindLevenshteinDistance("kitten", "sitting", nil, nil); got != 3 || path != "RMMMRMI" {
+		t.Errorf("FindLevenshteinDistance(nil costs) = %d %s, want 3 RMMMRMI", got, path)
+	}
+	if got := FindLevenshteinDistanceLinear("kitten", "sitting", Runes, nil, nil); got != 3 {
+		t.Errorf("FindLevenshteinDistanceLinear(nil costs) = %d, want 3", got)
+	}
+	if got, ok := FindLevenshteinDistanceBounded("kitten", "sitting", Runes, 3, nil, nil); got != 3 || !ok {
+		t.Errorf("FindLevenshteinDistanceBounded(nil costs) = %d %t, want 3 true", got, ok)
+	}
+	if got, _ := Distance([]int{1, 2, 3}, []int{2, 3}, nil); got != 1 {
+		t.Errorf("Distance(nil costs) = %d, want 1", got)
+	}
+
+	tests := []struct {
+		name     string
+		costs    *ClassicCosts
+		distance int
+	}{
+		{"nil costs", NewClassicCosts(nil, &SpecialRunes{Replace: 'k'}), 3},
+		{"nil runes", NewClassicCosts(&OperationCosts{Replace: 3, Insert: 2, Delete: 2}, nil), 8},
+		{"both nil", NewClassicCosts(nil, nil), 3},
+	}
+	for _, test := range tests {
+		if got, _ := FindLevenshteinDistance("kitten\x00", "sitting\x00", test.costs, debugLogger()); got != test.distance {
+			t.Errorf("%s: distance = %d, want %d", test.name, got, test.distance)
+		}
+	}
+}
@@ -0,0 +1,72 @@
+package vagner_fisher
+
+import (
+	"slices"
+	"strings"
+	"testing"
+)
+
+// gapCosts prices replacing one number by another by their difference.
+type gapCosts struct{}
+
+func (gapCosts) ReplaceCost(a, b int) int { return max(a-b, b-a) }
+func (gapCosts) InsertCost(b int) int     { return 10 }
+func (gapCosts) DeleteCost(a int) int     { return 10 }
+
+func TestDistanceOnWords(t *testing.T) {
+	a := strings.Fields("the quick brown fox")
+	b := strings.Fields("the quick red fox jumps")
+
+	distance, script := Distance(a, b, UnitCosts[string]{})
+	if distance != 2 || script.String() != "MMRMI" {
+		t.Errorf("Distance(words) = %d %s, want 2 MMRMI", distance, script)
+	}
+	if got, err := script.Apply(a); err != nil || !slices.Equal(got, b) {
+		t.Errorf("script applies to %q, %v", got, err)
+	}
+	if replaced := script[2]; replaced.From != "brown" || replaced.To != "red" {
+		t.Errorf("replace edit = %+v, want brown -> red", replaced)
+	}
+}
+
+func TestDistanceOnNumbers(t *testing.T) {
+	tests := []struct {
+		a, b     []int
+		costs    Costs[int]
+		distance int
+		ops      string
+	}{
+		{[]int{1, 2, 3, 4}, []int{1, 3, 4, 5}, UnitCosts[int]{}, 2, "MDMMI"},
+		{nil, []int{7, 8}, UnitCosts[int]{}, 2, "II"},
+		{[]int{1, 5, 9}, []int{2, 5, 7}, gapCosts{}, 3, "RMR"},
+		{[]int{1, 5}, []int{5, 40}, gapCosts{}, 20, "DMI"},
+	}
+	for _, test := range tests {
+		distance, script := Distance(test.a, test.b, test.costs)
+		if distance != test.distance || script.String() != test.ops {
+			t.Errorf("Distance(%v, %v) = %d %s, want %d %s", test.a, test.b, distance, script, test.distance, test.ops)
+		}
+		if script.Cost() != distance {
+			t.Errorf("Distance(%v, %v): script costs %d, want %d", test.a, test.b, script.Cost(), distance)
+		}
+
+		hirschbergDistance, hirschbergScript := FindTokenEditScriptHirschberg(test.a, test.b, test.costs, nil)
+		if hirschbergDistance != distance || hirschbergScript.Cost() != distance {
+			t.Errorf("FindTokenEditScriptHirschberg(%v, %v) = %d, script cost %d, want %d", test.a, test.b,
+				hirschbergDistance, hirschbergScript.Cost(), distance)
+		}
+	}
+}
+
+func TestDistanceMatchesStrings(t *testing.T) {
+	for _, cc := range testCosts {
+		for _, pair := range testPairs {
+			s1, s2 := pair[0], pair[1]
+			want, wantPath := FindLevenshteinDistance(s1, s2, cc.costs, debugLogger())
+			got, script := Distance(Split(s1, Runes), Split(s2, Runes), cc.costs)
+			if got != want || script.String() != wantPath {
+				t.Errorf("%s: Distance(%q, %q) = %d %s, want %d %s", cc.name, s1, s2, got, script, want, wantPath)
+			}
+		}
+	}
+}
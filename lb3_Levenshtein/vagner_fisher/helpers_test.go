@@ -0,0 +1,87 @@
+package vagner_fisher
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strings"
+
+	"lb3_Levenshtein/logger"
+)
+
+// quietLogger discards its output and has debug mode off.
+func quietLogger() *logger.Logger {
+	return logger.NewLogger(bufio.NewWriter(io.Discard))
+}
+
+// debugLogger discards its output but has debug mode on, so the classic DP
+// never takes the Myers shortcut.
+func debugLogger() *logger.Logger {
+	log := quietLogger()
+	log.SetDebugMode()
+	return log
+}
+
+type costCase struct {
+	name  string
+	costs CostModel
+}
+
+var testCosts = []costCase{
+	{"unit", NewCostTable(1, 1, 1, 1)},
+	{"cheap replace", NewCostTable(1, 2, 2, 1)},
+	{"expensive replace", NewCostTable(3, 1, 1, 1)},
+	{"asymmetric", NewCostTable(2, 1, 3, 1)},
+	{"special runes", NewClassicCosts(&OperationCosts{Replace: 2, Insert: 2, Delete: 1, SpecialReplace: 0, SpecialInsert: 1},
+		&SpecialRunes{Replace: 'a', Insert: 'b'})},
+}
+
+var testPairs = [][2]string{
+	{"", ""},
+	{"", "abc"},
+	{"abc", ""},
+	{"kitten", "sitting"},
+	{"flaw", "lawn"},
+	{"intention", "execution"},
+	{"abcabba", "cbabac"},
+	{"ab", "ba"},
+	{"привет", "привед"},
+	{strings.Repeat("abcde", 20), strings.Repeat("abdce", 19) + "ab"},
+	{strings.Repeat("ab", 70), strings.Repeat("ba", 66) + "c"},
+}
+
+// graphemePairs have a different distance between grapheme clusters than
+// between runes.
+var graphemePairs = [][2]string{
+	{"\U0001f1eb\U0001f1f7\U0001f1e9\U0001f1ea", "\U0001f1e9\U0001f1ea"},
+	{"e\u0301e\u0301x", "eex"},
+	{"\U0001f468\u200d\U0001f469", "\U0001f469\u200d\U0001f468"},
+}
+
+// replayPath walks an operations path over a and b and returns its cost. It
+// fails if a match pairs different symbols or the path does not end at the
+// end of both sequences.
+func replayPath(a, b []string, path string, costs CostModel) (int, error) {
+	i, j, cost := 0, 0, 0
+	for _, op := range path {
+		switch {
+		case op == Match && i < len(a) && j < len(b) && a[i] == b[j]:
+			i, j = i+1, j+1
+		case op == Replace && i < len(a) && j < len(b):
+			cost += costs.ReplaceCost(a[i], b[j])
+			i, j = i+1, j+1
+		case op == Insert && j < len(b):
+			cost += costs.InsertCost(b[j])
+			j++
+		case op == Delete && i < len(a):
+			cost += costs.DeleteCost(a[i])
+			i++
+		default:
+			return 0, fmt.Errorf("invalid %c at (%d, %d)", op, i, j)
+		}
+	}
+	if i != len(a) || j != len(b) {
+		return 0, fmt.Errorf("path ends at (%d, %d) instead of (%d, %d)", i, j, len(a), len(b))
+	}
+	return cost, nil
+}
@@ -0,0 +1,142 @@
+package vagner_fisher
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"lb3_Levenshtein/logger"
+)
+
+func lastRowCosts[T comparable](a, b []T, costs Costs[T]) []int {
+	m := len(b)
+	prev := make([]int, m+1)
+	cur := make([]int, m+1)
+
+	for j := 1; j <= m; j++ {
+		prev[j] = prev[j-1] + costs.InsertCost(b[j-1])
+	}
+
+	for i := 1; i <= len(a); i++ {
+		cur[0] = prev[0] + costs.DeleteCost(a[i-1])
+		for j := 1; j <= m; j++ {
+			diagTotal := prev[j-1] + matchCost(costs, a[i-1])
+			if a[i-1] != b[j-1] {
+				diagTotal = prev[j-1] + costs.ReplaceCost(a[i-1], b[j-1])
+			}
+			_, cur[j] = minOperation(
+				diagTotal,
+				cur[j-1]+costs.InsertCost(b[j-1]),
+				prev[j]+costs.DeleteCost(a[i-1]),
+			)
+		}
+		prev, cur = cur, prev
+	}
+
+	return prev
+}
+
+func FindLevenshteinDistanceLinear(s1, s2 string, seg Segmentation, costs CostModel, log Logger) int {
+	log, costs = orDiscard(log), orUnit(costs)
+	a, b := Split(s1, seg), Split(s2, seg)
+
+	log.LogMsg("Init", fmt.Sprintf("Calculating distance between strings of length %d and %d in two rows", len(a), len(b)),
+		logger.ColorCyan)
+
+	var distance int
+	if hasUnitCosts(a, b, costs) {
+		distance = myersDistance(a, b)
+	} else {
+		distance = lastRowCosts(a, b, costs)[len(b)]
+	}
+	log.LogMsg("Result", fmt.Sprintf("Final distance: %d", distance), logger.ColorGreen)
+
+	return distance
+}
+
+// LevenshteinDistanceFunc measures with FindLevenshteinDistanceLinear and no
+// logging, for packages that compare many strings such as bktree and pairwise.
+func LevenshteinDistanceFunc(costs CostModel) func(s1, s2 string) int {
+	return func(s1, s2 string) int {
+		return FindLevenshteinDistanceLinear(s1, s2, Runes, costs, discardLogger)
+	}
+}
+
+func FindLevenshteinDistanceHirschberg(s1, s2 string, seg Segmentation, costs CostModel, log Logger) (int, string) {
+	log, costs = orDiscard(log), orUnit(costs)
+	a, b := Split(s1, seg), Split(s2, seg)
+
+	log.LogMsg("Init", fmt.Sprintf("Calculating Hirschberg alignment of strings of length %d and %d", len(a), len(b)),
+		logger.ColorCyan)
+
+	var path strings.Builder
+	distance := hirschberg(a, b, costs, log, &path)
+	log.LogMsg("Result", fmt.Sprintf("Final distance: %d, Path: %s", distance, path.String()),
+		logger.ColorGreen)
+
+	return distance, path.String()
+}
+
+// FindTokenEditScriptHirschberg is the linear-memory counterpart of
+// FindTokenEditScript for long token sequences such as the lines of files.
+func FindTokenEditScriptHirschberg[T comparable](a, b []T, costs Costs[T], log Logger) (int, TokenScript[T]) {
+	log, costs = orDiscard(log), orUnit(costs)
+	log.LogMsg("Init", fmt.Sprintf("Calculating Hirschberg alignment of sequences of length %d and %d", len(a), len(b)),
+		logger.ColorCyan)
+
+	var path strings.Builder
+	distance := hirschberg(a, b, costs, log, &path)
+	return distance, scriptFromPath(a, b, path.String(), costs)
+}
+
+func hirschberg[T comparable](a, b []T, costs Costs[T], log Logger, path *strings.Builder) int {
+	n, m := len(a), len(b)
+
+	if n == 0 {
+		distance := 0
+		for _, symbol := range b {
+			distance += costs.InsertCost(symbol)
+			path.WriteRune(Insert)
+		}
+		return distance
+	}
+	if m == 0 {
+		distance := 0
+		for _, symbol := range a {
+			distance += costs.DeleteCost(symbol)
+			path.WriteRune(Delete)
+		}
+		return distance
+	}
+	if n == 1 || m == 1 {
+		distance, ops := findDistance(a, b, NoTransposition, costs, log)
+		path.WriteString(ops)
+		return distance
+	}
+
+	mid := n / 2
+	forward := lastRowCosts(a[:mid], b, costs)
+	backward := lastRowCosts(reversed(a[mid:]), reversed(b), costs)
+
+	split := 0
+	for k := 1; k <= m; k++ {
+		if forward[k]+backward[m-k] < forward[split]+backward[m-split] {
+			split = k
+		}
+	}
+
+	log.LogMsg("Hirschberg", fmt.Sprintf("Split %d x %d at row %d, column %d (cost %d)",
+		n, m, mid, split, forward[split]+backward[m-split]),
+		logger.ColorYellow)
+
+	left := hirschberg(a[:mid], b[:split], costs, log, path)
+	right := hirschberg(a[mid:], b[split:], costs, log, path)
+
+	return left + right
+}
+
+func reversed[T comparable](symbols []T) []T {
+	result := slices.Clone(symbols)
+	slices.Reverse(result)
+	return result
+}
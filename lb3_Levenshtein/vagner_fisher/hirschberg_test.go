@@ -0,0 +1,54 @@
+package vagner_fisher
+
+import "testing"
+
+func TestLinearMatchesFullDP(t *testing.T) {
+	for _, cc := range testCosts {
+		for _, pair := range testPairs {
+			s1, s2 := pair[0], pair[1]
+			want, _ := FindLevenshteinDistance(s1, s2, cc.costs, debugLogger())
+			if got := FindLevenshteinDistanceLinear(s1, s2, Runes, cc.costs, quietLogger()); got != want {
+				t.Errorf("%s: Linear(%q, %q) = %d, want %d", cc.name, s1, s2, got, want)
+			}
+		}
+	}
+}
+
+func TestLinearOnGraphemes(t *testing.T) {
+	for _, cc := range testCosts {
+		for _, pair := range graphemePairs {
+			s1, s2 := pair[0], pair[1]
+			want, _ := FindLevenshteinDistanceSegmented(s1, s2, Graphemes, cc.costs, debugLogger())
+			if got := FindLevenshteinDistanceLinear(s1, s2, Graphemes, cc.costs, quietLogger()); got != want {
+				t.Errorf("%s: Linear(%+q, %+q, Graphemes) = %d, want %d", cc.name, s1, s2, got, want)
+			}
+
+			distance, path := FindLevenshteinDistanceHirschberg(s1, s2, Graphemes, cc.costs, quietLogger())
+			cost, err := replayPath(Split(s1, Graphemes), Split(s2, Graphemes), path, cc.costs)
+			if distance != want || err != nil || cost != want {
+				t.Errorf("%s: Hirschberg(%+q, %+q, Graphemes) = %d, %s costs %d, %v, want %d", cc.name, s1, s2,
+					distance, path, cost, err, want)
+			}
+		}
+	}
+}
+
+func TestHirschbergMatchesFullDP(t *testing.T) {
+	// The single symbol pairs end the recursion on one side or the other.
+	pairs := append([][2]string{{"a", "bab"}, {"xyz", "y"}, {"a", "b"}, {"abc", "abc"}}, testPairs...)
+	for _, cc := range testCosts {
+		for _, pair := range pairs {
+			s1, s2 := pair[0], pair[1]
+			want, _ := FindLevenshteinDistance(s1, s2, cc.costs, debugLogger())
+
+			distance, path := FindLevenshteinDistanceHirschberg(s1, s2, Runes, cc.costs, quietLogger())
+			if distance != want {
+				t.Errorf("%s: Hirschberg(%q, %q) = %d, want %d", cc.name, s1, s2, distance, want)
+			}
+			cost, err := replayPath(Split(s1, Runes), Split(s2, Runes), path, cc.costs)
+			if err != nil || cost != want {
+				t.Errorf("%s: Hirschberg(%q, %q) path %s costs %d, %v, want %d", cc.name, s1, s2, path, cost, err, want)
+			}
+		}
+	}
+}
@@ -0,0 +1,263 @@
+package vagner_fisher
+
+import (
+	"fmt"
+	"math/bits"
+
+	"lb3_Levenshtein/logger"
+)
+
+const highBit = uint64(1) << 63
+
+// The bit-parallel algorithm keeps one column of the DP matrix as vertical
+// deltas: bit r of Pv (Mv) is set when D[r+1][j] - D[r][j] is +1 (-1).
+type myersMatrix[T comparable] struct {
+	a, b   []T
+	blocks int
+	peq    map[T][]uint64
+	pv, mv []uint64
+}
+
+// unitCheckLimit bounds the distinct symbol pairs hasUnitCosts asks a model
+// without a Unit method about; beyond it the check would cost as much as the
+// DP it is meant to skip.
+const unitCheckLimit = 4096
+
+func hasUnitCosts[T comparable](a, b []T, costs Costs[T]) bool {
+	if u, ok := any(costs).(UnitCost); ok {
+		return u.Unit()
+	}
+
+	source := make(map[T]bool)
+	for _, symbol := range a {
+		if !source[symbol] && (costs.DeleteCost(symbol) != 1 || matchCost(costs, symbol) != 0) {
+			return false
+		}
+		source[symbol] = true
+	}
+
+	target := make(map[T]bool)
+	for _, symbol := range b {
+		if !target[symbol] && costs.InsertCost(symbol) != 1 {
+			return false
+		}
+		target[symbol] = true
+	}
+
+	if len(source)*len(target) > unitCheckLimit {
+		return false
+	}
+	for x := range source {
+		for y := range target {
+			if x != y && costs.ReplaceCost(x, y) != 1 {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+func newMyersMatrix[T comparable](a, b []T, keepColumns bool) *myersMatrix[T] {
+	blocks := (len(a) + 63) / 64
+	peq := make(map[T][]uint64)
+	for i, symbol := range a {
+		if peq[symbol] == nil {
+			peq[symbol] = make([]uint64, blocks)
+		}
+		peq[symbol][i/64] |= 1 << (i % 64)
+	}
+
+	columns := 1
+	if keepColumns {
+		columns = len(b) + 1
+	}
+
+	return &myersMatrix[T]{
+		a:      a,
+		b:      b,
+		blocks: blocks,
+		peq:    peq,
+		pv:     make([]uint64, columns*blocks),
+		mv:     make([]uint64, columns*blocks),
+	}
+}
+
+func myersBlock(pv, mv, eq uint64, hin int) (uint64, uint64, int) {
+	var hinNeg uint64
+	if hin < 0 {
+		hinNeg = 1
+	}
+
+	xv := eq | mv
+	eq |= hinNeg
+	xh := (((eq & pv) + pv) ^ pv) | eq
+	ph := mv | ^(xh | pv)
+	mh := pv & xh
+
+	hout := 0
+	if ph&highBit != 0 {
+		hout = 1
+	} else if mh&highBit != 0 {
+		hout = -1
+	}
+
+	ph <<= 1
+	mh <<= 1
+	mh |= hinNeg
+	if hin > 0 {
+		ph |= 1
+	}
+
+	return mh | ^(xv | ph), ph & xv, hout
+}
+
+func (mm *myersMatrix[T]) fill() {
+	zero := make([]uint64, mm.blocks)
+	for k := range mm.blocks {
+		mm.pv[k] = ^uint64(0)
+	}
+
+	keepColumns := len(mm.pv) > mm.blocks
+	for j := 1; j <= len(mm.b); j++ {
+		prev, cur := 0, 0
+		if keepColumns {
+			prev, cur = (j-1)*mm.blocks, j*mm.blocks
+		}
+
+		eq, ok := mm.peq[mm.b[j-1]]
+		if !ok {
+			eq = zero
+		}
+
+		hin := 1
+		for k := range mm.blocks {
+			mm.pv[cur+k], mm.mv[cur+k], hin = myersBlock(mm.pv[prev+k], mm.mv[prev+k], eq[k], hin)
+		}
+	}
+}
+
+func (mm *myersMatrix[T]) column(j int) int {
+	if len(mm.pv) > mm.blocks {
+		return j * mm.blocks
+	}
+	return 0
+}
+
+func (mm *myersMatrix[T]) value(i, j int) int {
+	offset := mm.column(j)
+	value := j
+	for k := 0; k < i/64; k++ {
+		value += bits.OnesCount64(mm.pv[offset+k]) - bits.OnesCount64(mm.mv[offset+k])
+	}
+	if i%64 != 0 {
+		mask := uint64(1)<<(i%64) - 1
+		value += bits.OnesCount64(mm.pv[offset+i/64]&mask) - bits.OnesCount64(mm.mv[offset+i/64]&mask)
+	}
+	return value
+}
+
+func (mm *myersMatrix[T]) verticalDelta(i, j int) int {
+	offset := mm.column(j) + i/64
+	bit := uint64(1) << (i % 64)
+	switch {
+	case mm.pv[offset]&bit != 0:
+		return 1
+	case mm.mv[offset]&bit != 0:
+		return -1
+	}
+	return 0
+}
+
+// buildPath walks back from (n, m) with the same tie order as the classic DP.
+// D[i][j-1] is kept alongside D[i][j], so moving up only needs bit tests and
+// a popcount prefix sum is taken once per column.
+func (mm *myersMatrix[T]) buildPath(log Logger) string {
+	n, m := len(mm.a), len(mm.b)
+	path := make([]rune, 0, n+m)
+	i, j := n, m
+
+	cur := mm.value(i, j)
+	left := 0
+	if j > 0 {
+		left = mm.value(i, j-1)
+	}
+
+	for i > 0 || j > 0 {
+		if i == 0 {
+			path = append(path, Insert)
+			j--
+			continue
+		}
+		if j == 0 {
+			path = append(path, Delete)
+			i--
+			continue
+		}
+
+		up := cur - mm.verticalDelta(i-1, j)
+		diag := left - mm.verticalDelta(i-1, j-1)
+
+		diagTotal := diag
+		if mm.a[i-1] != mm.b[j-1] {
+			diagTotal++
+		}
+		op, _ := minOperation(diagTotal, left+1, up+1)
+		if op == Replace && mm.a[i-1] == mm.b[j-1] {
+			op = Match
+		}
+		path = append(path, op)
+
+		switch op {
+		case Insert:
+			j--
+			cur = left
+		case Delete:
+			i--
+			cur, left = up, diag
+			continue
+		default:
+			i--
+			j--
+			cur = diag
+		}
+		if j > 0 {
+			left = mm.value(i, j-1)
+		}
+	}
+
+	for k := 0; k < len(path)/2; k++ {
+		path[k], path[len(path)-1-k] = path[len(path)-1-k], path[k]
+	}
+
+	log.LogMsg("BuildPath", fmt.Sprintf("Final path: %s", string(path)), logger.ColorGreen)
+
+	return string(path)
+}
+
+func myersDistance[T comparable](a, b []T) int {
+	if len(a) > len(b) {
+		a, b = b, a
+	}
+	if len(a) == 0 {
+		return len(b)
+	}
+
+	mm := newMyersMatrix(a, b, false)
+	mm.fill()
+	return mm.value(len(a), len(b))
+}
+
+func myersPath[T comparable](a, b []T, log Logger) (int, string) {
+	log.LogMsg("Myers", fmt.Sprintf("Unit costs: using %d-word bit vectors for %d columns", (len(a)+63)/64, len(b)),
+		logger.ColorCyan)
+
+	mm := newMyersMatrix(a, b, true)
+	mm.fill()
+	distance := mm.value(len(a), len(b))
+	path := mm.buildPath(log)
+
+	log.LogMsg("Result", fmt.Sprintf("Final distance: %d, Path: %s", distance, path),
+		logger.ColorGreen)
+
+	return distance, path
+}
@@ -0,0 +1,86 @@
+package vagner_fisher
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestMyersMatchesFullDP(t *testing.T) {
+	tests := []struct {
+		name   string
+		s1, s2 string
+	}{
+		{"empty", "", ""},
+		{"empty source", "", "abc"},
+		{"empty target", "abc", ""},
+		{"equal", "levenshtein", "levenshtein"},
+		{"kitten", "kitten", "sitting"},
+		{"swap", "ab", "ba"},
+		{"unicode", "привет мир", "превед мир"},
+		{"block boundary", strings.Repeat("a", 64), strings.Repeat("a", 63) + "b"},
+		{"two blocks", strings.Repeat("abcdefgh", 9), strings.Repeat("abcdfegh", 8) + "xyz"},
+		{"three blocks", strings.Repeat("the quick brown fox ", 8), strings.Repeat("a quick brown dog ", 9)},
+		{"long target", "needle", strings.Repeat("haystack ", 20) + "needle"},
+		{"repeats", strings.Repeat("ab", 100), strings.Repeat("ba", 90)},
+	}
+
+	unit := testCosts[0]
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			a, b := Split(tt.s1, Runes), Split(tt.s2, Runes)
+			want, wantPath := findDistance(a, b, NoTransposition, unit.costs, debugLogger())
+			got, gotPath := myersPath(a, b, quietLogger())
+			if got != want || gotPath != wantPath {
+				t.Errorf("myersPath = %d %s, want %d %s", got, gotPath, want, wantPath)
+			}
+			if d := myersDistance(a, b); d != want {
+				t.Errorf("myersDistance = %d, want %d", d, want)
+			}
+		})
+	}
+}
+
+// allOnes is a model without a Unit method.
+type allOnes struct{}
+
+func (allOnes) ReplaceCost(a, b string) int { return 1 }
+func (allOnes) InsertCost(b string) int     { return 1 }
+func (allOnes) DeleteCost(a string) int     { return 1 }
+
+func runeRange(first rune, n int) string {
+	var b strings.Builder
+	for r := first; r < first+rune(n); r++ {
+		b.WriteRune(r)
+	}
+	return b.String()
+}
+
+func TestHasUnitCosts(t *testing.T) {
+	for _, cc := range testCosts {
+		want := cc.name == "unit"
+		if got := hasUnitCosts(Split("kitten", Runes), Split("sitting", Runes), cc.costs); got != want {
+			t.Errorf("%s: hasUnitCosts = %v, want %v", cc.name, got, want)
+		}
+	}
+
+	rules := NewCostTable(1, 1, 1, 1)
+	rules.Replace[[2]string{"x", "y"}] = 1
+	noSpecial := NewClassicCosts(&OperationCosts{Replace: 1, Insert: 1, Delete: 1}, &SpecialRunes{})
+	tests := []struct {
+		name  string
+		costs CostModel
+		a, b  string
+		want  bool
+	}{
+		{"table with rules", rules, "xyz", "zyx", false},
+		{"classic without special runes", noSpecial, "xyz", "zyx", true},
+		{"no Unit method", allOnes{}, "kitten", "sitting", true},
+		// Too many distinct pairs to ask a model without a Unit method about.
+		{"no Unit method, large alphabets", allOnes{}, runeRange('Ā', 100), runeRange('Ѐ', 100), false},
+	}
+	for _, test := range tests {
+		if got := hasUnitCosts(Split(test.a, Runes), Split(test.b, Runes), test.costs); got != test.want {
+			t.Errorf("%s: hasUnitCosts = %v, want %v", test.name, got, test.want)
+		}
+	}
+}
@@ -0,0 +1,138 @@
+package vagner_fisher
+
+import (
+	"fmt"
+	"iter"
+	"math/big"
+	"math/rand"
+
+	"lb3_Levenshtein/logger"
+)
+
+// OptimalScripts describes every minimum-cost edit script between two strings
+// as paths through the DP matrix that only use edges achieving the cell value.
+type OptimalScripts struct {
+	a, b  []string
+	costs CostModel
+	dp    [][]int
+	count [][]*big.Int
+}
+
+type optimalStep struct {
+	op   rune
+	i, j int
+}
+
+func FindOptimalScripts(s1, s2 string, seg Segmentation, costs CostModel, log Logger) *OptimalScripts {
+	log, costs = orDiscard(log), orUnit(costs)
+	a, b := Split(s1, seg), Split(s2, seg)
+	n, m := len(a), len(b)
+	dp, _, _ := fillMatrices(a, b, NoTransposition, costs, log)
+
+	o := &OptimalScripts{a: a, b: b, costs: costs, dp: dp}
+	o.count = make([][]*big.Int, n+1)
+	for i := range o.count {
+		o.count[i] = make([]*big.Int, m+1)
+	}
+
+	for i := 0; i <= n; i++ {
+		for j := 0; j <= m; j++ {
+			if i == 0 && j == 0 {
+				o.count[i][j] = big.NewInt(1)
+				continue
+			}
+			total := new(big.Int)
+			for _, p := range o.predecessors(i, j) {
+				total.Add(total, o.count[p.i][p.j])
+			}
+			o.count[i][j] = total
+		}
+	}
+
+	log.LogMsg("OptimalScripts", fmt.Sprintf("Distance %d, %s optimal scripts", dp[n][m], o.count[n][m]),
+		logger.ColorGreen)
+
+	return o
+}
+
+func (o *OptimalScripts) predecessors(i, j int) []optimalStep {
+	var steps []optimalStep
+	if i > 0 && j > 0 {
+		if o.a[i-1] == o.b[j-1] {
+			if o.dp[i-1][j-1]+matchCost(o.costs, o.a[i-1]) == o.dp[i][j] {
+				steps = append(steps, optimalStep{Match, i - 1, j - 1})
+			}
+		} else if o.dp[i-1][j-1]+o.costs.ReplaceCost(o.a[i-1], o.b[j-1]) == o.dp[i][j] {
+			steps = append(steps, optimalStep{Replace, i - 1, j - 1})
+		}
+	}
+	if j > 0 && o.dp[i][j-1]+o.costs.InsertCost(o.b[j-1]) == o.dp[i][j] {
+		steps = append(steps, optimalStep{Insert, i, j - 1})
+	}
+	if i > 0 && o.dp[i-1][j]+o.costs.DeleteCost(o.a[i-1]) == o.dp[i][j] {
+		steps = append(steps, optimalStep{Delete, i - 1, j})
+	}
+	return steps
+}
+
+func (o *OptimalScripts) Distance() int {
+	return o.dp[len(o.a)][len(o.b)]
+}
+
+func (o *OptimalScripts) Count() *big.Int {
+	return new(big.Int).Set(o.count[len(o.a)][len(o.b)])
+}
+
+// All yields the scripts in the tie order of FindLevenshteinDistance, so the
+// first one is the script it returns.
+func (o *OptimalScripts) All() iter.Seq[string] {
+	return func(yield func(string) bool) {
+		path := make([]rune, 0, len(o.a)+len(o.b))
+
+		var walk func(i, j int) bool
+		walk = func(i, j int) bool {
+			if i == 0 && j == 0 {
+				script := make([]rune, len(path))
+				for k, op := range path {
+					script[len(path)-1-k] = op
+				}
+				return yield(string(script))
+			}
+			for _, p := range o.predecessors(i, j) {
+				path = append(path, p.op)
+				if !walk(p.i, p.j) {
+					return false
+				}
+				path = path[:len(path)-1]
+			}
+			return true
+		}
+
+		walk(len(o.a), len(o.b))
+	}
+}
+
+// Sample draws one optimal script uniformly at random: walking back from the
+// end, each predecessor is chosen in proportion to the scripts reaching it.
+func (o *OptimalScripts) Sample(r *rand.Rand) string {
+	i, j := len(o.a), len(o.b)
+	path := make([]rune, 0, i+j)
+	pick := new(big.Int)
+
+	for i > 0 || j > 0 {
+		pick.Rand(r, o.count[i][j])
+		for _, p := range o.predecessors(i, j) {
+			if pick.Cmp(o.count[p.i][p.j]) < 0 {
+				path = append(path, p.op)
+				i, j = p.i, p.j
+				break
+			}
+			pick.Sub(pick, o.count[p.i][p.j])
+		}
+	}
+
+	for k := 0; k < len(path)/2; k++ {
+		path[k], path[len(path)-1-k] = path[len(path)-1-k], path[k]
+	}
+	return string(path)
+}
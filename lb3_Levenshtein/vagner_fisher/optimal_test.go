@@ -0,0 +1,43 @@
+package vagner_fisher
+
+import (
+	"math/rand"
+	"slices"
+	"testing"
+)
+
+func TestOptimalScripts(t *testing.T) {
+	unit := NewCostTable(1, 1, 1, 1)
+	tests := []struct {
+		s1, s2   string
+		seg      Segmentation
+		distance int
+		scripts  []string
+	}{
+		{"ab", "ba", Runes, 2, []string{"RR", "IMD", "DMI"}},
+		{"abc", "abc", Runes, 0, []string{"MMM"}},
+		{"", "ab", Runes, 2, []string{"II"}},
+		{"e\u0301x", "xe\u0301", Graphemes, 2, []string{"RR", "IMD", "DMI"}},
+	}
+	for _, test := range tests {
+		optimal := FindOptimalScripts(test.s1, test.s2, test.seg, unit, quietLogger())
+		if optimal.Distance() != test.distance {
+			t.Errorf("FindOptimalScripts(%+q, %+q): distance %d, want %d", test.s1, test.s2, optimal.Distance(), test.distance)
+		}
+		if got := optimal.Count().Int64(); got != int64(len(test.scripts)) {
+			t.Errorf("FindOptimalScripts(%+q, %+q): count %d, want %d", test.s1, test.s2, got, len(test.scripts))
+		}
+
+		all := slices.Sorted(optimal.All())
+		want := slices.Sorted(slices.Values(test.scripts))
+		if !slices.Equal(all, want) {
+			t.Errorf("FindOptimalScripts(%+q, %+q): All = %v, want %v", test.s1, test.s2, all, want)
+		}
+		r := rand.New(rand.NewSource(1))
+		for range 10 {
+			if sample := optimal.Sample(r); !slices.Contains(want, sample) {
+				t.Errorf("FindOptimalScripts(%+q, %+q): Sample = %s, not optimal", test.s1, test.s2, sample)
+			}
+		}
+	}
+}
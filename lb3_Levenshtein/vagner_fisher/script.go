@@ -0,0 +1,293 @@
+package vagner_fisher
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// TokenEdit is one step of an alignment. SourceIndex and TargetIndex are symbol
+// positions in s1 and s2; an insert points at the s1 position it precedes and
+// a delete at the s2 position it precedes.
+//
+// A transposition swaps From (at SourceIndex) with To (at TargetIndex). When
+// the swapped symbols are not adjacent, the Delete and Insert edits of the
+// symbols between them directly follow the Transpose edit.
+type TokenEdit[T comparable] struct {
+	Op          rune
+	SourceIndex int
+	TargetIndex int
+	From        T
+	To          T
+	Cost        int
+}
+
+// TokenScript is an edit script over any comparable symbols. EditScript is
+// the string version with helpers that work on whole strings.
+type TokenScript[T comparable] []TokenEdit[T]
+
+type Edit = TokenEdit[string]
+
+type EditScript []Edit
+
+func quoteSymbol(symbol any) string {
+	if s, ok := symbol.(string); ok {
+		return strconv.Quote(s)
+	}
+	return fmt.Sprint(symbol)
+}
+
+func (e TokenEdit[T]) String() string {
+	from, to := quoteSymbol(e.From), quoteSymbol(e.To)
+	switch e.Op {
+	case Insert:
+		from = `""`
+	case Delete:
+		to = `""`
+	case Transpose:
+		return fmt.Sprintf("%c s1[%d] %s <-> %s s2[%d] (cost %d)", e.Op, e.SourceIndex, from, to, e.TargetIndex, e.Cost)
+	}
+	return fmt.Sprintf("%c s1[%d] %s -> s2[%d] %s (cost %d)", e.Op, e.SourceIndex, from, e.TargetIndex, to, e.Cost)
+}
+
+func (s TokenScript[T]) String() string {
+	ops := make([]rune, len(s))
+	for k, e := range s {
+		ops[k] = e.Op
+	}
+	return string(ops)
+}
+
+func (s TokenScript[T]) Cost() int {
+	total := 0
+	for _, e := range s {
+		total += e.Cost
+	}
+	return total
+}
+
+func (s EditScript) String() string {
+	return TokenScript[string](s).String()
+}
+
+func (s EditScript) Cost() int {
+	return TokenScript[string](s).Cost()
+}
+
+func isTranspositionGap[T comparable](open TokenEdit[T], e TokenEdit[T], deleted, inserted int) bool {
+	return (e.Op == Delete && e.SourceIndex == open.SourceIndex+1+deleted) ||
+		(e.Op == Insert && e.TargetIndex == open.TargetIndex+1+inserted)
+}
+
+// Alignment lists the script as aligned columns. Every transposition gets a
+// second column after its gap edits, swapping the same symbols back, so that
+// reading the columns in order walks both strings left to right.
+func (s TokenScript[T]) Alignment() []TokenEdit[T] {
+	columns := make([]TokenEdit[T], 0, len(s))
+
+	var open *TokenEdit[T]
+	deleted, inserted := 0, 0
+	closeTransposition := func() {
+		if open == nil {
+			return
+		}
+		columns = append(columns, TokenEdit[T]{
+			Op:          Transpose,
+			SourceIndex: open.SourceIndex + 1 + deleted,
+			TargetIndex: open.TargetIndex + 1 + inserted,
+			From:        open.To,
+			To:          open.From,
+		})
+		open = nil
+	}
+
+	for k, e := range s {
+		if open != nil && !isTranspositionGap(*open, e, deleted, inserted) {
+			closeTransposition()
+		}
+
+		switch e.Op {
+		case Insert:
+			if open != nil {
+				inserted++
+			}
+		case Delete:
+			if open != nil {
+				deleted++
+			}
+		case Transpose:
+			open = &s[k]
+			deleted, inserted = 0, 0
+		}
+		columns = append(columns, e)
+	}
+	closeTransposition()
+
+	return columns
+}
+
+func (s EditScript) Alignment() []Edit {
+	return TokenScript[string](s).Alignment()
+}
+
+// Apply replays the script on a token sequence.
+func (s TokenScript[T]) Apply(source []T) ([]T, error) {
+	var out []T
+	rest := source
+
+	for _, e := range s.Alignment() {
+		switch e.Op {
+		case Match, Replace, Transpose, Delete:
+			if len(rest) == 0 || rest[0] != e.From {
+				return nil, fmt.Errorf("edit %c at s1[%d] expects %s", e.Op, e.SourceIndex, quoteSymbol(e.From))
+			}
+			rest = rest[1:]
+			if e.Op != Delete {
+				out = append(out, e.To)
+			}
+		case Insert:
+			out = append(out, e.To)
+		default:
+			return nil, fmt.Errorf("unknown operation %q", e.Op)
+		}
+	}
+
+	if len(rest) != 0 {
+		return nil, fmt.Errorf("script leaves %d source symbols unconsumed", len(rest))
+	}
+
+	return out, nil
+}
+
+func (s EditScript) Apply(s1 string) (string, error) {
+	var out strings.Builder
+	rest := s1
+
+	for _, e := range s.Alignment() {
+		switch e.Op {
+		case Match, Replace, Transpose, Delete:
+			if !strings.HasPrefix(rest, e.From) {
+				return "", fmt.Errorf("edit %c at s1[%d] expects %q, found %q", e.Op, e.SourceIndex, e.From, rest)
+			}
+			rest = rest[len(e.From):]
+			out.WriteString(e.To)
+		case Insert:
+			out.WriteString(e.To)
+		default:
+			return "", fmt.Errorf("unknown operation %q", e.Op)
+		}
+	}
+
+	if rest != "" {
+		return "", fmt.Errorf("script leaves %q of the source unconsumed", rest)
+	}
+
+	return out.String(), nil
+}
+
+// Invert returns the script turning s2 back into s1. Costs are carried over
+// as recorded; reprice the result if the cost model is not symmetric.
+func (s TokenScript[T]) Invert() TokenScript[T] {
+	inverted := make(TokenScript[T], len(s))
+	for k, e := range s {
+		op := e.Op
+		switch op {
+		case Insert:
+			op = Delete
+		case Delete:
+			op = Insert
+		}
+		inverted[k] = TokenEdit[T]{
+			Op:          op,
+			SourceIndex: e.TargetIndex,
+			TargetIndex: e.SourceIndex,
+			From:        e.To,
+			To:          e.From,
+			Cost:        e.Cost,
+		}
+	}
+	return inverted
+}
+
+func (s EditScript) Invert() EditScript {
+	return EditScript(TokenScript[string](s).Invert())
+}
+
+func (s EditScript) Reprice(costs CostModel) EditScript {
+	repriced := make(EditScript, len(s))
+	for k, e := range s {
+		e.Cost = editCost(e, costs)
+		repriced[k] = e
+	}
+	return repriced
+}
+
+func editCost[T comparable](e TokenEdit[T], costs Costs[T]) int {
+	switch e.Op {
+	case Match:
+		return matchCost(costs, e.From)
+	case Replace:
+		return costs.ReplaceCost(e.From, e.To)
+	case Insert:
+		return costs.InsertCost(e.To)
+	case Delete:
+		return costs.DeleteCost(e.From)
+	case Transpose:
+		return transposeCost(costs, e.From, e.To)
+	}
+	return 0
+}
+
+// Validate replays the script on s1, checks that it produces s2 and that its
+// cost adds up to distance. With a non-nil cost model every edit is repriced
+// and must match its recorded cost.
+func (s EditScript) Validate(s1, s2 string, costs CostModel, distance int) error {
+	result, err := s.Apply(s1)
+	if err != nil {
+		return err
+	}
+	if result != s2 {
+		return fmt.Errorf("script produces %q instead of %q", result, s2)
+	}
+
+	for _, e := range s {
+		if e.Op == Match && e.From != e.To {
+			return fmt.Errorf("match at s1[%d] pairs different symbols %q and %q", e.SourceIndex, e.From, e.To)
+		}
+		if costs != nil {
+			if expected := editCost(e, costs); expected != e.Cost {
+				return fmt.Errorf("edit %v should cost %d", e, expected)
+			}
+		}
+	}
+
+	if total := s.Cost(); total != distance {
+		return fmt.Errorf("script costs %d, distance is %d", total, distance)
+	}
+	return nil
+}
+
+func scriptFromPath[T comparable](a, b []T, path string, costs Costs[T]) TokenScript[T] {
+	script := make(TokenScript[T], 0, len(path))
+	i, j := 0, 0
+
+	for _, op := range path {
+		e := TokenEdit[T]{Op: op, SourceIndex: i, TargetIndex: j}
+		switch op {
+		case Match, Replace:
+			e.From, e.To = a[i], b[j]
+			i++
+			j++
+		case Insert:
+			e.To = b[j]
+			j++
+		case Delete:
+			e.From = a[i]
+			i++
+		}
+		e.Cost = editCost(e, costs)
+		script = append(script, e)
+	}
+
+	return script
+}
@@ -0,0 +1,74 @@
+package vagner_fisher
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestInvertRoundTrip(t *testing.T) {
+	variants := []Transposition{NoTransposition, OptimalStringAlignment, UnrestrictedTransposition}
+	pairs := append(slices.Clone(testPairs), [][2]string{{"abc", "ca"}, {"abcdef", "bafdce"}, {"ca", "abc"}}...)
+
+	for _, variant := range variants {
+		for _, cc := range testCosts {
+			for _, pair := range pairs {
+				s1, s2 := pair[0], pair[1]
+				distance, script := FindEditScript(s1, s2, Runes, variant, cc.costs, debugLogger())
+				if err := script.Validate(s1, s2, cc.costs, distance); err != nil {
+					t.Errorf("%s %d: FindEditScript(%q, %q): %v", cc.name, variant, s1, s2, err)
+					continue
+				}
+
+				inverted := script.Invert()
+				if err := inverted.Validate(s2, s1, nil, distance); err != nil {
+					t.Errorf("%s %d: Invert of %q -> %q: %v", cc.name, variant, s1, s2, err)
+				}
+				if got := inverted.Invert(); !slices.Equal(got, script) {
+					t.Errorf("%s %d: Invert is not an involution for %q -> %q", cc.name, variant, s1, s2)
+				}
+			}
+		}
+	}
+}
+
+func TestScriptWithTranspositions(t *testing.T) {
+	costs := NewCostTable(2, 1, 1, 1)
+	tests := []struct {
+		variant  Transposition
+		s1, s2   string
+		distance int
+		ops      string
+	}{
+		{OptimalStringAlignment, "ab", "ba", 1, "T"},
+		{OptimalStringAlignment, "abcdef", "bacdfe", 2, "TMMT"},
+		{UnrestrictedTransposition, "ca", "abc", 2, "TI"},
+	}
+	for _, test := range tests {
+		distance, script := FindEditScript(test.s1, test.s2, Runes, test.variant, costs, debugLogger())
+		if distance != test.distance || script.String() != test.ops {
+			t.Errorf("FindEditScript(%q, %q, %d) = %d %s, want %d %s", test.s1, test.s2, test.variant,
+				distance, script, test.distance, test.ops)
+		}
+		if got, err := script.Invert().Apply(test.s2); err != nil || got != test.s1 {
+			t.Errorf("Invert of %q -> %q applies to %q, %v", test.s1, test.s2, got, err)
+		}
+	}
+}
+
+func TestValidateRejects(t *testing.T) {
+	costs := NewCostTable(1, 1, 1, 1)
+	distance, script := FindEditScript("kitten", "sitting", Runes, NoTransposition, costs, debugLogger())
+
+	if err := script.Validate("kitten", "sitten", costs, distance); err == nil {
+		t.Errorf("Validate accepts the wrong target")
+	}
+	if err := script.Validate("kitten", "sitting", costs, distance+1); err == nil {
+		t.Errorf("Validate accepts the wrong distance")
+	}
+	if err := script.Validate("kitten", "sitting", NewCostTable(2, 1, 1, 1), distance); err == nil {
+		t.Errorf("Validate accepts edits priced by another model")
+	}
+	if _, err := script.Apply("mitten"); err == nil {
+		t.Errorf("Apply accepts a source the script does not start from")
+	}
+}
@@ -0,0 +1,150 @@
+package vagner_fisher
+
+import (
+	"fmt"
+	"io"
+	"iter"
+
+	"lb3_Levenshtein/logger"
+)
+
+// Occurrence is a match of the pattern in the text at symbol positions
+// [Start, End) whose edit distance to the pattern is Cost.
+type Occurrence struct {
+	Start int
+	End   int
+	Cost  int
+}
+
+// ApproximateMatcher runs the Sellers algorithm: the DP column over the
+// pattern is advanced one text symbol at a time, and row 0 stays zero so a
+// match may start anywhere in the text. Each cell also remembers the text
+// position its optimal path started at.
+type ApproximateMatcher struct {
+	pattern []string
+	seg     Segmentation
+	k       int
+	costs   CostModel
+	log     Logger
+
+	position   int
+	column     []int
+	start      []int
+	prevColumn []int
+	prevStart  []int
+}
+
+func NewApproximateMatcher(pattern string, seg Segmentation, k int, costs CostModel, log Logger) *ApproximateMatcher {
+	log, costs = orDiscard(log), orUnit(costs)
+	p := Split(pattern, seg)
+	am := &ApproximateMatcher{
+		pattern:    p,
+		seg:        seg,
+		k:          k,
+		costs:      costs,
+		log:        log,
+		column:     make([]int, len(p)+1),
+		start:      make([]int, len(p)+1),
+		prevColumn: make([]int, len(p)+1),
+		prevStart:  make([]int, len(p)+1),
+	}
+
+	for i := 1; i <= len(p); i++ {
+		am.column[i] = am.column[i-1] + costs.DeleteCost(p[i-1])
+	}
+
+	log.LogMsg("Search", fmt.Sprintf("Searching for %q with at most %d edits", pattern, k), logger.ColorCyan)
+
+	return am
+}
+
+// Next consumes one text symbol and reports the match ending after it, if
+// its cost is at most k.
+func (am *ApproximateMatcher) Next(symbol string) (Occurrence, bool) {
+	am.column, am.prevColumn = am.prevColumn, am.column
+	am.start, am.prevStart = am.prevStart, am.start
+
+	am.column[0], am.start[0] = 0, am.position+1
+	for i := 1; i <= len(am.pattern); i++ {
+		diagTotal := am.prevColumn[i-1]
+		if am.pattern[i-1] != symbol {
+			diagTotal += am.costs.ReplaceCost(am.pattern[i-1], symbol)
+		}
+		op, cost := minOperation(
+			diagTotal,
+			am.prevColumn[i]+am.costs.InsertCost(symbol),
+			am.column[i-1]+am.costs.DeleteCost(am.pattern[i-1]),
+		)
+
+		am.column[i] = cost
+		switch op {
+		case Replace:
+			am.start[i] = am.prevStart[i-1]
+		case Insert:
+			am.start[i] = am.prevStart[i]
+		case Delete:
+			am.start[i] = am.start[i-1]
+		}
+	}
+	am.position++
+
+	n := len(am.pattern)
+	if am.column[n] > am.k {
+		return Occurrence{}, false
+	}
+
+	match := Occurrence{Start: am.start[n], End: am.position, Cost: am.column[n]}
+	am.log.LogMsg("Occurrence", fmt.Sprintf("Text [%d, %d) at cost %d", match.Start, match.End, match.Cost),
+		logger.ColorGreen)
+	return match, true
+}
+
+// Matches streams the text and yields every match as soon as its end is read.
+// A grapheme cluster only ends when the rune after it or the end of the text
+// is read.
+func (am *ApproximateMatcher) Matches(text io.RuneReader) iter.Seq2[Occurrence, error] {
+	return func(yield func(Occurrence, error) bool) {
+		var breaker graphemeBreaker
+		var cluster []rune
+		for {
+			r, _, err := text.ReadRune()
+			if err == io.EOF {
+				if len(cluster) > 0 {
+					if match, ok := am.Next(string(cluster)); ok {
+						yield(match, nil)
+					}
+				}
+				return
+			}
+			if err != nil {
+				yield(Occurrence{}, err)
+				return
+			}
+
+			if am.seg != Graphemes {
+				if match, ok := am.Next(string(r)); ok && !yield(match, nil) {
+					return
+				}
+				continue
+			}
+			if breaker.next(r) {
+				if match, ok := am.Next(string(cluster)); ok && !yield(match, nil) {
+					return
+				}
+				cluster = cluster[:0]
+			}
+			cluster = append(cluster, r)
+		}
+	}
+}
+
+func FindApproximateMatches(pattern, text string, seg Segmentation, k int, costs CostModel, log Logger) []Occurrence {
+	am := NewApproximateMatcher(pattern, seg, k, costs, log)
+	var matches []Occurrence
+	for _, symbol := range Split(text, seg) {
+		if match, ok := am.Next(symbol); ok {
+			matches = append(matches, match)
+		}
+	}
+	return matches
+}
@@ -0,0 +1,49 @@
+package vagner_fisher
+
+import (
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestApproximateMatches(t *testing.T) {
+	unit := NewCostTable(1, 1, 1, 1)
+	tests := []struct {
+		pattern, text string
+		seg           Segmentation
+		k             int
+		want          []Occurrence
+	}{
+		{"abc", "xxabcxx", Runes, 0, []Occurrence{{2, 5, 0}}},
+		{"abc", "xxabcxx", Runes, 1, []Occurrence{{2, 4, 1}, {2, 5, 0}, {2, 6, 1}}},
+		{"abc", "xabdxaxcx", Runes, 1, []Occurrence{{1, 3, 1}, {1, 4, 1}, {5, 8, 1}}},
+		// Overlapping windows each end at their own position.
+		{"aa", "aaaa", Runes, 0, []Occurrence{{0, 2, 0}, {1, 3, 0}, {2, 4, 0}}},
+		{"abc", "abcabc", Runes, 1, []Occurrence{{0, 2, 1}, {0, 3, 0}, {0, 4, 1}, {3, 5, 1}, {3, 6, 0}}},
+		{"abc", "xyz", Runes, 1, nil},
+		{"", "ab", Runes, 0, []Occurrence{{1, 1, 0}, {2, 2, 0}}},
+		// Positions count clusters, and a mark never matches alone.
+		{"e\u0301", "xe\u0301e", Graphemes, 0, []Occurrence{{1, 2, 0}}},
+		{"e\u0301", "xe\u0301e", Runes, 0, []Occurrence{{1, 3, 0}}},
+	}
+	for _, test := range tests {
+		got := FindApproximateMatches(test.pattern, test.text, test.seg, test.k, unit, quietLogger())
+		if !slices.Equal(got, test.want) {
+			t.Errorf("FindApproximateMatches(%+q, %+q, %d, %d) = %v, want %v", test.pattern, test.text, test.seg,
+				test.k, got, test.want)
+		}
+
+		var streamed []Occurrence
+		am := NewApproximateMatcher(test.pattern, test.seg, test.k, unit, quietLogger())
+		for match, err := range am.Matches(strings.NewReader(test.text)) {
+			if err != nil {
+				t.Fatalf("Matches(%+q): %v", test.text, err)
+			}
+			streamed = append(streamed, match)
+		}
+		if !slices.Equal(streamed, test.want) {
+			t.Errorf("Matches(%+q, %+q, %d, %d) = %v, want %v", test.pattern, test.text, test.seg, test.k,
+				streamed, test.want)
+		}
+	}
+}
@@ -0,0 +1,183 @@
+package vagner_fisher
+
+import (
+	"unicode"
+	"unicode/utf8"
+)
+
+type Segmentation int
+
+const (
+	Runes Segmentation = iota
+	Graphemes
+)
+
+type graphemeClass int
+
+const (
+	classOther graphemeClass = iota
+	classCR
+	classLF
+	classControl
+	classExtend
+	classZWJ
+	classSpacingMark
+	classRegionalIndicator
+	classPictographic
+	classHangulL
+	classHangulV
+	classHangulT
+	classHangulLV
+	classHangulLVT
+)
+
+func Split(s string, seg Segmentation) []string {
+	if seg == Graphemes {
+		return splitGraphemes(s)
+	}
+	return splitRunes(s)
+}
+
+func splitRunes(s string) []string {
+	symbols := make([]string, 0, utf8.RuneCountInString(s))
+	for i := 0; i < len(s); {
+		_, size := utf8.DecodeRuneInString(s[i:])
+		symbols = append(symbols, s[i:i+size])
+		i += size
+	}
+	return symbols
+}
+
+// splitGraphemes follows the extended grapheme cluster rules of UAX #29,
+// except for Prepend and Indic conjunct handling.
+func splitGraphemes(s string) []string {
+	var symbols []string
+	var breaker graphemeBreaker
+	start := 0
+
+	for i := 0; i < len(s); {
+		r, size := utf8.DecodeRuneInString(s[i:])
+		if breaker.next(r) {
+			symbols = append(symbols, s[start:i])
+			start = i
+		}
+		i += size
+	}
+
+	if start < len(s) {
+		symbols = append(symbols, s[start:])
+	}
+	return symbols
+}
+
+// graphemeBreaker finds cluster boundaries one rune at a time, for callers
+// that read the text as a stream.
+type graphemeBreaker struct {
+	started        bool
+	prev           graphemeClass
+	inPictographic bool
+	regionalCount  int
+}
+
+// next reports whether a new cluster starts at r.
+func (g *graphemeBreaker) next(r rune) bool {
+	cur := classifyGrapheme(r)
+
+	boundary := g.started && isGraphemeBoundary(g.prev, cur, g.inPictographic, g.regionalCount)
+	if boundary {
+		g.inPictographic = false
+		g.regionalCount = 0
+	}
+
+	switch cur {
+	case classPictographic:
+		g.inPictographic = true
+	case classExtend, classZWJ:
+	default:
+		g.inPictographic = false
+	}
+	if cur == classRegionalIndicator {
+		g.regionalCount++
+	} else {
+		g.regionalCount = 0
+	}
+
+	g.started = true
+	g.prev = cur
+	return boundary
+}
+
+func isGraphemeBoundary(prev, cur graphemeClass, inPictographic bool, regionalCount int) bool {
+	switch {
+	case prev == classCR && cur == classLF:
+		return false
+	case prev == classCR || prev == classLF || prev == classControl:
+		return true
+	case cur == classCR || cur == classLF || cur == classControl:
+		return true
+	case prev == classHangulL && (cur == classHangulL || cur == classHangulV || cur == classHangulLV || cur == classHangulLVT):
+		return false
+	case (prev == classHangulLV || prev == classHangulV) && (cur == classHangulV || cur == classHangulT):
+		return false
+	case (prev == classHangulLVT || prev == classHangulT) && cur == classHangulT:
+		return false
+	case cur == classExtend || cur == classZWJ || cur == classSpacingMark:
+		return false
+	case prev == classZWJ && cur == classPictographic && inPictographic:
+		return false
+	case prev == classRegionalIndicator && cur == classRegionalIndicator && regionalCount%2 == 1:
+		return false
+	}
+	return true
+}
+
+func classifyGrapheme(r rune) graphemeClass {
+	switch {
+	case r == '\r':
+		return classCR
+	case r == '\n':
+		return classLF
+	case r == 0x200D:
+		return classZWJ
+	case r == 0x200C, r >= 0xE0020 && r <= 0xE007F, r >= 0x1F3FB && r <= 0x1F3FF:
+		return classExtend
+	case r >= 0x1F1E6 && r <= 0x1F1FF:
+		return classRegionalIndicator
+	case unicode.In(r, unicode.Mn, unicode.Me):
+		return classExtend
+	case unicode.Is(unicode.Mc, r):
+		return classSpacingMark
+	case unicode.In(r, unicode.Cc, unicode.Cf, unicode.Zl, unicode.Zp):
+		return classControl
+	case r >= 0x1100 && r <= 0x115F, r >= 0xA960 && r <= 0xA97C:
+		return classHangulL
+	case r >= 0x1160 && r <= 0x11A7, r >= 0xD7B0 && r <= 0xD7C6:
+		return classHangulV
+	case r >= 0x11A8 && r <= 0x11FF, r >= 0xD7CB && r <= 0xD7FB:
+		return classHangulT
+	case r >= 0xAC00 && r <= 0xD7A3:
+		if (r-0xAC00)%28 == 0 {
+			return classHangulLV
+		}
+		return classHangulLVT
+	case isPictographic(r):
+		return classPictographic
+	}
+	return classOther
+}
+
+func isPictographic(r rune) bool {
+	switch {
+	case r == 0x00A9, r == 0x00AE, r == 0x203C, r == 0x2049, r == 0x2122, r == 0x2139:
+		return true
+	case r >= 0x2194 && r <= 0x21AA, r >= 0x231A && r <= 0x23FA, r >= 0x25AA && r <= 0x25FE:
+		return true
+	case r >= 0x2600 && r <= 0x27BF, r >= 0x2934 && r <= 0x2935, r >= 0x2B05 && r <= 0x2B55:
+		return true
+	case r == 0x3030, r == 0x303D, r == 0x3297, r == 0x3299:
+		return true
+	case r >= 0x1F000 && r <= 0x1FAFF:
+		return true
+	}
+	return false
+}
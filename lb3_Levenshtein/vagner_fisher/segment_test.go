@@ -0,0 +1,68 @@
+package vagner_fisher
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestSplitGraphemes(t *testing.T) {
+	tests := []struct {
+		name string
+		s    string
+		want []string
+	}{
+		{"empty", "", nil},
+		{"ascii", "abc", []string{"a", "b", "c"}},
+		{"combining marks", "e\u0301a\u0308\u0323b", []string{"e\u0301", "a\u0308\u0323", "b"}},
+		{"leading mark", "\u0301a", []string{"\u0301", "a"}},
+		{"hangul L V T", "\u1100\u1161\u11a8\u1100", []string{"\u1100\u1161\u11a8", "\u1100"}},
+		{"hangul LV T", "\uac00\u11a8\uac01", []string{"\uac00\u11a8", "\uac01"}},
+		{"hangul LVT V", "\uac01\u1161", []string{"\uac01", "\u1161"}},
+		{"zwj emoji", "\U0001f468\u200d\U0001f469\u200d\U0001f467x", []string{"\U0001f468\u200d\U0001f469\u200d\U0001f467", "x"}},
+		{"emoji modifier", "\U0001f44d\U0001f3fd", []string{"\U0001f44d\U0001f3fd"}},
+		{"zwj after letter", "a\u200d\U0001f469", []string{"a\u200d", "\U0001f469"}},
+		{"regional pair", "\U0001f1eb\U0001f1f7", []string{"\U0001f1eb\U0001f1f7"}},
+		{"regional odd third", "\U0001f1eb\U0001f1f7\U0001f1e9", []string{"\U0001f1eb\U0001f1f7", "\U0001f1e9"}},
+		{"regional two pairs", "\U0001f1eb\U0001f1f7\U0001f1e9\U0001f1ea", []string{"\U0001f1eb\U0001f1f7", "\U0001f1e9\U0001f1ea"}},
+		{"crlf", "a\r\nb\n\r", []string{"a", "\r\n", "b", "\n", "\r"}},
+		{"mark after newline", "\n\u0301", []string{"\n", "\u0301"}},
+	}
+	for _, test := range tests {
+		if got := Split(test.s, Graphemes); !slices.Equal(got, test.want) {
+			t.Errorf("%s: Split(%+q, Graphemes) = %+q, want %+q", test.name, test.s, got, test.want)
+		}
+	}
+}
+
+func TestSplitRunes(t *testing.T) {
+	got := Split("e\u0301\r\n", Runes)
+	want := []string{"e", "\u0301", "\r", "\n"}
+	if !slices.Equal(got, want) {
+		t.Errorf("Split(Runes) = %+q, want %+q", got, want)
+	}
+}
+
+func TestEditScriptOnGraphemes(t *testing.T) {
+	costs := NewCostTable(1, 1, 1, 1)
+	tests := []struct {
+		s1, s2   string
+		seg      Segmentation
+		distance int
+		ops      string
+	}{
+		{"cafe\u0301", "cafe", Runes, 1, "MMMMD"},
+		{"cafe\u0301", "cafe", Graphemes, 1, "MMMR"},
+		{"\U0001f1eb\U0001f1f7\U0001f1e9\U0001f1ea", "\U0001f1e9\U0001f1ea", Graphemes, 1, "DM"},
+		{"a\r\nb", "a\nb", Graphemes, 1, "MRM"},
+	}
+	for _, test := range tests {
+		distance, script := FindEditScript(test.s1, test.s2, test.seg, NoTransposition, costs, debugLogger())
+		if distance != test.distance || script.String() != test.ops {
+			t.Errorf("FindEditScript(%+q, %+q, %d) = %d %s, want %d %s", test.s1, test.s2, test.seg,
+				distance, script, test.distance, test.ops)
+		}
+		if got, err := script.Apply(test.s1); err != nil || got != test.s2 {
+			t.Errorf("script of %+q -> %+q applies to %+q, %v", test.s1, test.s2, got, err)
+		}
+	}
+}
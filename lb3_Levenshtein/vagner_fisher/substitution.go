@@ -0,0 +1,202 @@
+package vagner_fisher
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+
+	"lb3_Levenshtein/logger"
+)
+
+// SubstitutionMatrix holds similarity scores such as BLOSUM62, read from the
+// NCBI text format: a header line of column symbols followed by one row per
+// symbol. Pairs missing from the matrix are looked up again upper-cased, as
+// the NCBI matrices only list capital letters, and then fall back to the '*'
+// row and column, or the ['*', '*'] score when both symbols are unknown.
+type SubstitutionMatrix struct {
+	Name   string
+	Scores map[[2]string]int
+}
+
+var (
+	BLOSUM62 = mustParseSubstitutionMatrix("BLOSUM62", blosum62)
+	PAM250   = mustParseSubstitutionMatrix("PAM250", pam250)
+)
+
+func (sm *SubstitutionMatrix) Score(a, b string) int {
+	if score, ok := sm.Scores[[2]string{a, b}]; ok {
+		return score
+	}
+	a, b = strings.ToUpper(a), strings.ToUpper(b)
+	for _, pair := range [][2]string{{a, b}, {a, "*"}, {"*", b}, {"*", "*"}} {
+		if score, ok := sm.Scores[pair]; ok {
+			return score
+		}
+	}
+	return 0
+}
+
+// scoreCosts turns a maximised score into a minimised cost by negation, so
+// the distance DP and its backtracking can be reused unchanged.
+type scoreCosts struct {
+	matrix *SubstitutionMatrix
+	gap    int
+}
+
+func (sc scoreCosts) ReplaceCost(a, b string) int { return -sc.matrix.Score(a, b) }
+func (sc scoreCosts) MatchCost(a string) int      { return -sc.matrix.Score(a, a) }
+func (sc scoreCosts) InsertCost(b string) int     { return sc.gap }
+func (sc scoreCosts) DeleteCost(a string) int     { return sc.gap }
+
+func (sc scoreCosts) String() string {
+	return fmt.Sprintf("Matrix: %s, Gap: %d", sc.matrix.Name, sc.gap)
+}
+
+// FindNeedlemanWunschAlignment finds the global alignment with the highest
+// score, where every gap symbol costs gap points. Edit costs in the returned
+// script hold the score each column contributes.
+func FindNeedlemanWunschAlignment(s1, s2 string, seg Segmentation, matrix *SubstitutionMatrix, gap int, log Logger) (int, EditScript) {
+	log = orDiscard(log)
+	a, b := Split(s1, seg), Split(s2, seg)
+	costs := scoreCosts{matrix: matrix, gap: gap}
+
+	n, m := len(a), len(b)
+	dp, ops, transFrom := fillMatrices(a, b, NoTransposition, costs, log)
+	script := buildPath(n, m, costs, ops, dp, transFrom, a, b, log)
+	for k := range script {
+		script[k].Cost = -script[k].Cost
+	}
+
+	log.LogMsg("Result", fmt.Sprintf("Alignment score: %d, Path: %s", -dp[n][m], script),
+		logger.ColorGreen)
+
+	return -dp[n][m], EditScript(script)
+}
+
+func LoadSubstitutionMatrix(path string) (*SubstitutionMatrix, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	matrix, err := ParseSubstitutionMatrix(path, file)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return matrix, nil
+}
+
+func ParseSubstitutionMatrix(name string, r io.Reader) (*SubstitutionMatrix, error) {
+	matrix := &SubstitutionMatrix{Name: name, Scores: make(map[[2]string]int)}
+	scanner := bufio.NewScanner(r)
+
+	var columns []string
+	for line := 1; scanner.Scan(); line++ {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) == 0 || strings.HasPrefix(fields[0], "#") {
+			continue
+		}
+
+		if columns == nil {
+			for _, field := range fields {
+				symbol, err := parseSymbol(field)
+				if err != nil {
+					return nil, fmt.Errorf("line %d: %w", line, err)
+				}
+				columns = append(columns, symbol)
+			}
+			continue
+		}
+
+		if len(fields) != len(columns)+1 {
+			return nil, fmt.Errorf("line %d: expected %d scores, got %d", line, len(columns), len(fields)-1)
+		}
+		row, err := parseSymbol(fields[0])
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %w", line, err)
+		}
+		for k, field := range fields[1:] {
+			score, err := strconv.Atoi(field)
+			if err != nil {
+				return nil, fmt.Errorf("line %d: invalid score: %w", line, err)
+			}
+			matrix.Scores[[2]string{row, columns[k]}] = score
+		}
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	if columns == nil {
+		return nil, fmt.Errorf("no header line")
+	}
+	return matrix, nil
+}
+
+func mustParseSubstitutionMatrix(name, text string) *SubstitutionMatrix {
+	matrix, err := ParseSubstitutionMatrix(name, strings.NewReader(text))
+	if err != nil {
+		panic(err)
+	}
+	return matrix
+}
+
+const blosum62 = `
+   A  R  N  D  C  Q  E  G  H  I  L  K  M  F  P  S  T  W  Y  V  B  Z  X  *
+A  4 -1 -2 -2  0 -1 -1  0 -2 -1 -1 -1 -1 -2 -1  1  0 -3 -2  0 -2 -1  0 -4
+R -1  5  0 -2 -3  1  0 -2  0 -3 -2  2 -1 -3 -2 -1 -1 -3 -2 -3 -1  0 -1 -4
+N -2  0  6  1 -3  0  0  0  1 -3 -3  0 -2 -3 -2  1  0 -4 -2 -3  3  0 -1 -4
+D -2 -2  1  6 -3  0  2 -1 -1 -3 -4 -1 -3 -3 -1  0 -1 -4 -3 -3  4  1 -1 -4
+C  0 -3 -3 -3  9 -3 -4 -3 -3 -1 -1 -3 -1 -2 -3 -1 -1 -2 -2 -1 -3 -3 -2 -4
+Q -1  1  0  0 -3  5  2 -2  0 -3 -2  1  0 -3 -1  0 -1 -2 -1 -2  0  3 -1 -4
+E -1  0  0  2 -4  2  5 -2  0 -3 -3  1 -2 -3 -1  0 -1 -3 -2 -2  1  4 -1 -4
+G  0 -2  0 -1 -3 -2 -2  6 -2 -4 -4 -2 -3 -3 -2  0 -2 -2 -3 -3 -1 -2 -1 -4
+H -2  0  1 -1 -3  0  0 -2  8 -3 -3 -1 -2 -1 -2 -1 -2 -2  2 -3  0  0 -1 -4
+I -1 -3 -3 -3 -1 -3 -3 -4 -3  4  2 -3  1  0 -3 -2 -1 -3 -1  3 -3 -3 -1 -4
+L -1 -2 -3 -4 -1 -2 -3 -4 -3  2  4 -2  2  0 -3 -2 -1 -2 -1  1 -4 -3 -1 -4
+K -1  2  0 -1 -3  1  1 -2 -1 -3 -2  5 -1 -3 -1  0 -1 -3 -2 -2  0  1 -1 -4
+M -1 -1 -2 -3 -1  0 -2 -3 -2  1  2 -1  5  0 -2 -1 -1 -1 -1  1 -3 -1 -1 -4
+F -2 -3 -3 -3 -2 -3 -3 -3 -1  0  0 -3  0  6 -4 -2 -2  1  3 -1 -3 -3 -1 -4
+P -1 -2 -2 -1 -3 -1 -1 -2 -2 -3 -3 -1 -2 -4  7 -1 -1 -4 -3 -2 -2 -1 -2 -4
+S  1 -1  1  0 -1  0  0  0 -1 -2 -2  0 -1 -2 -1  4  1 -3 -2 -2  0  0  0 -4
+T  0 -1  0 -1 -1 -1 -1 -2 -2 -1 -1 -1 -1 -2 -1  1  5 -2 -2  0 -1 -1  0 -4
+W -3 -3 -4 -4 -2 -2 -3 -2 -2 -3 -2 -3 -1  1 -4 -3 -2 11  2 -3 -4 -3 -2 -4
+Y -2 -2 -2 -3 -2 -1 -2 -3  2 -1 -1 -2 -1  3 -3 -2 -2  2  7 -1 -3 -2 -1 -4
+V  0 -3 -3 -3 -1 -2 -2 -3 -3  3  1 -2  1 -1 -2 -2  0 -3 -1  4 -3 -2 -1 -4
+B -2 -1  3  4 -3  0  1 -1  0 -3 -4  0 -3 -3 -2  0 -1 -4 -3 -3  4  1 -1 -4
+Z -1  0  0  1 -3  3  4 -2  0 -3 -3  1 -1 -3 -1  0 -1 -3 -2 -2  1  4 -1 -4
+X  0 -1 -1 -1 -2 -1 -1 -1 -1 -1 -1 -1 -1 -1 -2  0  0 -2 -1 -1 -1 -1 -1 -4
+* -4 -4 -4 -4 -4 -4 -4 -4 -4 -4 -4 -4 -4 -4 -4 -4 -4 -4 -4 -4 -4 -4 -4  1
+`
+
+const pam250 = `
+   A  R  N  D  C  Q  E  G  H  I  L  K  M  F  P  S  T  W  Y  V  B  Z  X  *
+A  2 -2  0  0 -2  0  0  1 -1 -1 -2 -1 -1 -3  1  1  1 -6 -3  0  0  0  0 -8
+R -2  6  0 -1 -4  1 -1 -3  2 -2 -3  3  0 -4  0  0 -1  2 -4 -2 -1  0 -1 -8
+N  0  0  2  2 -4  1  1  0  2 -2 -3  1 -2 -3  0  1  0 -4 -2 -2  2  1  0 -8
+D  0 -1  2  4 -5  2  3  1  1 -2 -4  0 -3 -6 -1  0  0 -7 -4 -2  3  3 -1 -8
+C -2 -4 -4 -5 12 -5 -5 -3 -3 -2 -6 -5 -5 -4 -3  0 -2 -8  0 -2 -4 -5 -3 -8
+Q  0  1  1  2 -5  4  2 -1  3 -2 -2  1 -1 -5  0 -1 -1 -5 -4 -2  1  3 -1 -8
+E  0 -1  1  3 -5  2  4  0  1 -2 -3  0 -2 -5 -1  0  0 -7 -4 -2  3  3 -1 -8
+G  1 -3  0  1 -3 -1  0  5 -2 -3 -4 -2 -3 -5  0  1  0 -7 -5 -1  0  0 -1 -8
+H -1  2  2  1 -3  3  1 -2  6 -2 -2  0 -2 -2  0 -1 -1 -3  0 -2  1  2 -1 -8
+I -1 -2 -2 -2 -2 -2 -2 -3 -2  5  2 -2  2  1 -2 -1  0 -5 -1  4 -2 -2 -1 -8
+L -2 -3 -3 -4 -6 -2 -3 -4 -2  2  6 -3  4  2 -3 -3 -2 -2 -1  2 -3 -3 -1 -8
+K -1  3  1  0 -5  1  0 -2  0 -2 -3  5  0 -5 -1  0  0 -3 -4 -2  1  0 -1 -8
+M -1  0 -2 -3 -5 -1 -2 -3 -2  2  4  0  6  0 -2 -2 -1 -4 -2  2 -2 -2 -1 -8
+F -3 -4 -3 -6 -4 -5 -5 -5 -2  1  2 -5  0  9 -5 -3 -3  0  7 -1 -4 -5 -2 -8
+P  1  0  0 -1 -3  0 -1  0  0 -2 -3 -1 -2 -5  6  1  0 -6 -5 -1 -1  0 -1 -8
+S  1  0  1  0  0 -1  0  1 -1 -1 -3  0 -2 -3  1  2  1 -2 -3 -1  0  0  0 -8
+T  1 -1  0  0 -2 -1  0  0 -1  0 -2  0 -1 -3  0  1  3 -5 -3  0  0 -1  0 -8
+W -6  2 -4 -7 -8 -5 -7 -7 -3 -5 -2 -3 -4  0 -6 -2 -5 17  0 -6 -5 -6 -4 -8
+Y -3 -4 -2 -4  0 -4 -4 -5  0 -1 -1 -4 -2  7 -5 -3 -3  0 10 -2 -3 -4 -2 -8
+V  0 -2 -2 -2 -2 -2 -2 -1 -2  4  2 -2  2 -1 -1 -1  0 -6 -2  4 -2 -2 -1 -8
+B  0 -1  2  3 -4  1  3  0  1 -2 -3  1 -2 -4 -1  0  0 -5 -3 -2  3  2 -1 -8
+Z  0  0  1  3 -5  3  3  0  2 -2 -3  0 -2 -5  0  0 -1 -6 -4 -2  2  3 -1 -8
+X  0 -1  0 -1 -3 -1 -1 -1 -1 -1 -1 -1 -1 -2 -1  0  0 -4 -2 -1 -1 -1 -1 -8
+* -8 -8 -8 -8 -8 -8 -8 -8 -8 -8 -8 -8 -8 -8 -8 -8 -8 -8 -8 -8 -8 -8 -8  1
+`
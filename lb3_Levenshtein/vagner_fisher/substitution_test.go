@@ -0,0 +1,66 @@
+package vagner_fisher
+
+import "testing"
+
+func TestNeedlemanWunschBLOSUM62(t *testing.T) {
+	tests := []struct {
+		s1, s2 string
+		seg    Segmentation
+		gap    int
+		score  int
+		ops    string
+	}{
+		// The example of Durbin et al. with BLOSUM62 instead of BLOSUM50.
+		{"HEAGAWGHEE", "PAWHEAE", Runes, 8, -8, "DDRDMMRRRM"},
+		{"HEAGAWGHEE", "PAWHEAE", Runes, 4, 12, "DDRDMMDMMIM"},
+		{"MEANLY", "MEANLY", Runes, 8, 31, "MMMMMM"},
+		{"AWHEAE", "AWHAE", Runes, 4, 28, "MMMDMM"},
+		{"AWHEAE", "AWHAE", Runes, 8, 24, "MMMDMM"},
+		// Lower-case symbols score like their capitals.
+		{"heagawghee", "pawheae", Runes, 8, -8, "DDRDMMRRRM"},
+		{"acgt", "acgt", Runes, 4, 24, "MMMM"},
+		{"A\u0301W", "AW", Runes, 4, 11, "MDM"},
+		{"A\u0301W", "AW", Graphemes, 4, 7, "RM"},
+	}
+	for _, test := range tests {
+		score, script := FindNeedlemanWunschAlignment(test.s1, test.s2, test.seg, BLOSUM62, test.gap, quietLogger())
+		if score != test.score || script.String() != test.ops {
+			t.Errorf("FindNeedlemanWunschAlignment(%q, %q, %d) = %d %s, want %d %s", test.s1, test.s2, test.gap,
+				score, script, test.score, test.ops)
+		}
+
+		total := 0
+		for _, edit := range script {
+			total += edit.Cost
+		}
+		if total != score {
+			t.Errorf("FindNeedlemanWunschAlignment(%q, %q, %d): script scores %d, want %d", test.s1, test.s2,
+				test.gap, total, score)
+		}
+	}
+}
+
+func TestScoreUnknownSymbols(t *testing.T) {
+	tests := []struct {
+		a, b  string
+		score int
+	}{
+		{"W", "W", 11},
+		{"w", "W", 11},
+		{"a", "c", 0},
+		{"W", "1", -4},
+		{"1", "W", -4},
+		{"1", "2", 1},
+		{"\u00e9", "\u00e9", 1},
+	}
+	for _, test := range tests {
+		if got := BLOSUM62.Score(test.a, test.b); got != test.score {
+			t.Errorf("BLOSUM62.Score(%q, %q) = %d, want %d", test.a, test.b, got, test.score)
+		}
+	}
+
+	noStar := &SubstitutionMatrix{Name: "tiny", Scores: map[[2]string]int{{"A", "A"}: 2}}
+	if got := noStar.Score("x", "y"); got != 0 {
+		t.Errorf("Score without a '*' row = %d, want 0", got)
+	}
+}
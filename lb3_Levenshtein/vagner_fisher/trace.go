@@ -0,0 +1,205 @@
+package vagner_fisher
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"iter"
+)
+
+// Tracer is an optional extension of Logger. The classic DP behind
+// FindLevenshteinDistance, FindEditScript and Distance reports every step it
+// takes to a logger that implements it, and never takes the Myers shortcut.
+type Tracer interface {
+	TraceEvent(e TraceEvent)
+}
+
+type EventKind string
+
+const (
+	// EventInit starts a trace with the symbols of both sequences.
+	EventInit EventKind = "init"
+	// EventCell fills DP cell (I, J) with Cost by Op, the cheapest of Candidates.
+	EventCell EventKind = "cell"
+	// EventSpecial marks a special rune priced at cell (I, J).
+	EventSpecial EventKind = "special"
+	// EventBacktrack steps from cell (I, J) to Next with the edits of Op.
+	EventBacktrack EventKind = "backtrack"
+	// EventResult ends a trace with the distance in Cost and the Path.
+	EventResult EventKind = "result"
+)
+
+type Cell struct {
+	I int `json:"i"`
+	J int `json:"j"`
+}
+
+// Candidate is the total cost of reaching a cell by one operation.
+type Candidate struct {
+	Op   string `json:"op"`
+	Cost int    `json:"cost"`
+}
+
+// TraceEvent is one step of the DP. Which fields are set depends on Kind.
+// Symbols are strings; tokens of other types are formatted with fmt.
+type TraceEvent struct {
+	Kind       EventKind   `json:"kind"`
+	I          int         `json:"i"`
+	J          int         `json:"j"`
+	Op         string      `json:"op,omitempty"`
+	Cost       int         `json:"cost"`
+	Candidates []Candidate `json:"candidates,omitempty"`
+	Symbol     string      `json:"symbol,omitempty"`
+	From       string      `json:"from,omitempty"`
+	To         string      `json:"to,omitempty"`
+	Next       *Cell       `json:"next,omitempty"`
+	Source     []string    `json:"source,omitempty"`
+	Target     []string    `json:"target,omitempty"`
+	Path       string      `json:"path,omitempty"`
+}
+
+// Trace collects the events of a run. It is a Logger that drops the text
+// messages, so it can be passed wherever a logger is expected. It is not safe
+// for concurrent use.
+type Trace struct {
+	Events []TraceEvent `json:"events"`
+}
+
+func (t *Trace) TraceEvent(e TraceEvent) {
+	t.Events = append(t.Events, e)
+}
+
+func (t *Trace) LogMsg(title, message, color string)                     {}
+func (t *Trace) LogRuneMatrix(title string, data [][]rune, color string) {}
+func (t *Trace) LogCostMatrix(title string, data [][]int, color string)  {}
+
+func (t *Trace) Enabled() bool {
+	return true
+}
+
+func (t *Trace) WriteJSON(w io.Writer) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(t)
+}
+
+func ReadTrace(r io.Reader) (*Trace, error) {
+	var t Trace
+	if err := json.NewDecoder(r).Decode(&t); err != nil {
+		return nil, fmt.Errorf("reading trace: %w", err)
+	}
+	return &t, nil
+}
+
+// ReplayState is the DP as it stands after Event. Cells not computed yet have
+// a zero op. Path holds the cells visited by the backtrack so far.
+type ReplayState struct {
+	Event    TraceEvent
+	Step     int
+	Source   []string
+	Target   []string
+	DP       [][]int
+	Ops      [][]rune
+	Path     []Cell
+	Distance int
+	Done     bool
+}
+
+// Replay applies the events in order and yields the state after each of
+// them. The same state is updated in place, so it must be copied to be kept
+// past the next step. A malformed trace yields an error and stops.
+func (t *Trace) Replay() iter.Seq2[*ReplayState, error] {
+	return func(yield func(*ReplayState, error) bool) {
+		state := &ReplayState{}
+		for k, e := range t.Events {
+			if err := state.apply(e); err != nil {
+				yield(nil, fmt.Errorf("event %d: %w", k, err))
+				return
+			}
+			state.Event, state.Step = e, k
+			if !yield(state, nil) {
+				return
+			}
+		}
+	}
+}
+
+func (s *ReplayState) apply(e TraceEvent) error {
+	if e.Kind == EventInit {
+		s.Source, s.Target = e.Source, e.Target
+		s.DP = make([][]int, len(e.Source)+1)
+		s.Ops = make([][]rune, len(e.Source)+1)
+		for i := range s.DP {
+			s.DP[i] = make([]int, len(e.Target)+1)
+			s.Ops[i] = make([]rune, len(e.Target)+1)
+		}
+		s.Path, s.Distance, s.Done = nil, 0, false
+		return nil
+	}
+
+	if s.DP == nil {
+		return fmt.Errorf("%s event before init", e.Kind)
+	}
+	if !s.contains(Cell{e.I, e.J}) || e.Next != nil && !s.contains(*e.Next) {
+		return fmt.Errorf("cell (%d, %d) is outside the matrix", e.I, e.J)
+	}
+
+	switch e.Kind {
+	case EventCell:
+		if len(e.Op) != 1 {
+			return fmt.Errorf("invalid operation %q", e.Op)
+		}
+		s.DP[e.I][e.J], s.Ops[e.I][e.J] = e.Cost, rune(e.Op[0])
+	case EventSpecial:
+	case EventBacktrack:
+		if len(s.Path) == 0 {
+			s.Path = append(s.Path, Cell{e.I, e.J})
+		}
+		if e.Next != nil {
+			s.Path = append(s.Path, *e.Next)
+		}
+	case EventResult:
+		if len(s.Path) == 0 {
+			s.Path = append(s.Path, Cell{e.I, e.J})
+		}
+		s.Distance, s.Done = e.Cost, true
+	default:
+		return fmt.Errorf("unknown event kind %q", e.Kind)
+	}
+	return nil
+}
+
+func (s *ReplayState) contains(c Cell) bool {
+	return c.I >= 0 && c.I < len(s.DP) && c.J >= 0 && c.J < len(s.DP[c.I])
+}
+
+func symbolText[T comparable](symbol T) string {
+	if s, ok := any(symbol).(string); ok {
+		return s
+	}
+	return fmt.Sprint(symbol)
+}
+
+func symbolTexts[T comparable](symbols []T) []string {
+	texts := make([]string, len(symbols))
+	for k, symbol := range symbols {
+		texts[k] = symbolText(symbol)
+	}
+	return texts
+}
+
+// traceBacktrack reports the edits buildPath appended while stepping from
+// cell to next. A transposition step also covers the inserts and deletes
+// between the swapped symbols, so Op and the symbols are those of its last
+// edit and Cost is the total.
+func traceBacktrack[T comparable](tracer Tracer, cell, next Cell, edits TokenScript[T]) {
+	last := edits[len(edits)-1]
+	e := TraceEvent{Kind: EventBacktrack, I: cell.I, J: cell.J, Op: string(last.Op), Cost: edits.Cost(), Next: &next}
+	if last.Op != Insert {
+		e.From = symbolText(last.From)
+	}
+	if last.Op != Delete {
+		e.To = symbolText(last.To)
+	}
+	tracer.TraceEvent(e)
+}
@@ -0,0 +1,123 @@
+package vagner_fisher
+
+import (
+	"bytes"
+	"slices"
+	"strings"
+	"testing"
+)
+
+// finalState replays the whole trace and returns the last state.
+func finalState(t *testing.T, trace *Trace) *ReplayState {
+	t.Helper()
+	var last *ReplayState
+	for state, err := range trace.Replay() {
+		if err != nil {
+			t.Fatal(err)
+		}
+		last = state
+	}
+	return last
+}
+
+func TestReplayReproducesMatrix(t *testing.T) {
+	for _, variant := range []Transposition{NoTransposition, OptimalStringAlignment} {
+		for _, cc := range testCosts {
+			for _, pair := range testPairs {
+				s1, s2 := pair[0], pair[1]
+				a, b := Split(s1, Runes), Split(s2, Runes)
+				// The long pairs make traces of tens of thousands of events.
+				if len(a)*len(b) > 1000 {
+					continue
+				}
+				dp, ops, _ := fillMatrices(a, b, variant, cc.costs, discardLogger)
+
+				trace := &Trace{}
+				distance, script := FindEditScript(s1, s2, Runes, variant, cc.costs, trace)
+
+				var encoded bytes.Buffer
+				if err := trace.WriteJSON(&encoded); err != nil {
+					t.Fatal(err)
+				}
+				decoded, err := ReadTrace(&encoded)
+				if err != nil {
+					t.Fatal(err)
+				}
+
+				for _, tr := range []*Trace{trace, decoded} {
+					state := finalState(t, tr)
+					if !state.Done || state.Distance != distance {
+						t.Errorf("%s %d: replay of (%q, %q) ends with distance %d, done %t, want %d", cc.name, variant,
+							s1, s2, state.Distance, state.Done, distance)
+					}
+					if !slices.Equal(state.Source, a) || !slices.Equal(state.Target, b) {
+						t.Errorf("%s %d: replay of (%q, %q) has symbols %q and %q", cc.name, variant, s1, s2,
+							state.Source, state.Target)
+					}
+					if !slices.EqualFunc(state.DP, dp, slices.Equal) || !slices.EqualFunc(state.Ops, ops, slices.Equal) {
+						t.Errorf("%s %d: replay of (%q, %q) does not reproduce the matrices", cc.name, variant, s1, s2)
+					}
+
+					path := state.Path
+					if path[0] != (Cell{len(a), len(b)}) || path[len(path)-1] != (Cell{}) {
+						t.Errorf("%s %d: replay of (%q, %q) backtracks from %v to %v", cc.name, variant, s1, s2,
+							path[0], path[len(path)-1])
+					}
+					if len(path) > len(script)+1 {
+						t.Errorf("%s %d: replay of (%q, %q) visits %d cells for %d edits", cc.name, variant, s1, s2,
+							len(path), len(script))
+					}
+				}
+			}
+		}
+	}
+}
+
+func TestReplayStepByStep(t *testing.T) {
+	trace := &Trace{}
+	FindEditScript("ab", "b", Runes, NoTransposition, NewCostTable(1, 1, 1, 1), trace)
+
+	steps := 0
+	for state, err := range trace.Replay() {
+		if err != nil {
+			t.Fatal(err)
+		}
+		if state.Step != steps || state.Event.Kind != trace.Events[steps].Kind {
+			t.Errorf("step %d replays event %d %s", steps, state.Step, state.Event.Kind)
+		}
+		steps++
+		if steps == 3 {
+			break
+		}
+	}
+	if steps != 3 {
+		t.Errorf("replay stopped after %d steps, want 3", steps)
+	}
+}
+
+func TestReplayMalformed(t *testing.T) {
+	tests := []struct {
+		name  string
+		trace string
+		err   string
+	}{
+		{"cell before init", `{"events": [{"kind": "cell", "i": 0, "j": 0, "op": "M"}]}`, "event 0: cell event before init"},
+		{"outside", `{"events": [{"kind": "init", "source": ["a"]}, {"kind": "cell", "i": 2, "j": 0, "op": "D"}]}`,
+			"event 1: cell (2, 0) is outside the matrix"},
+		{"long op", `{"events": [{"kind": "init"}, {"kind": "cell", "op": "MD"}]}`, `event 1: invalid operation "MD"`},
+		{"unknown kind", `{"events": [{"kind": "init"}, {"kind": "jump"}]}`, `event 1: unknown event kind "jump"`},
+	}
+	for _, test := range tests {
+		trace, err := ReadTrace(strings.NewReader(test.trace))
+		if err != nil {
+			t.Fatal(err)
+		}
+		var replayErr error
+		for _, err := range trace.Replay() {
+			replayErr = err
+		}
+		if replayErr == nil || replayErr.Error() != test.err {
+			t.Errorf("%s: Replay error %v, want %s", test.name, replayErr, test.err)
+		}
+	}
+}
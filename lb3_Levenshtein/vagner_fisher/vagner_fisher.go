@@ -2,30 +2,73 @@ package vagner_fisher
 
 import (
 	"fmt"
+	"strings"
 
 	"lb3_Levenshtein/logger"
 )
 
-var debug bool = false
+var discardLogger Logger = logger.Nop{}
 
+// Logger receives the debug output of the algorithms. color is one of the
+// ANSI colours of the logger package, which adapters that do not write to a
+// terminal ignore. *logger.Logger, logger.Nop, logger.Slog and
+// logger.JSONLines implement it.
 type Logger interface {
-	LogMsg(title, message string)
-	LogRuneMatrix(title string, data [][]rune)
-	LogCostMatrix(title string, data [][]int)
-	SetDebugMode()
+	LogMsg(title, message, color string)
+	LogRuneMatrix(title string, data [][]rune, color string)
+	LogCostMatrix(title string, data [][]int, color string)
+}
+
+// orDiscard lets every entry point accept a nil logger.
+func orDiscard(log Logger) Logger {
+	if log == nil {
+		return discardLogger
+	}
+	return log
+}
+
+// orUnit lets every entry point accept nil costs, which mean unit costs.
+func orUnit[T comparable](costs Costs[T]) Costs[T] {
+	if costs == nil {
+		return UnitCosts[T]{}
+	}
+	return costs
+}
+
+// debugging reports whether log wants the debug output, so that the fast
+// paths that skip the matrices are only taken when nobody would see them.
+// Loggers without an Enabled method always do.
+func debugging(log Logger) bool {
+	if l, ok := log.(interface{ Enabled() bool }); ok {
+		return l.Enabled()
+	}
+	return true
 }
 
 const (
-	Match   = 'M'
-	Replace = 'R'
-	Insert  = 'I'
-	Delete  = 'D'
+	Match     = 'M'
+	Replace   = 'R'
+	Insert    = 'I'
+	Delete    = 'D'
+	Transpose = 'T'
 )
 
+type Transposition int
+
+const (
+	NoTransposition Transposition = iota
+	OptimalStringAlignment
+	UnrestrictedTransposition
+)
+
+// OperationCosts are the costs of ClassicCosts. Transpose is only used with
+// transpositions on; nil means it costs the same as Replace, so a model built
+// without it never makes transpositions free.
 type OperationCosts struct {
 	Replace        int
 	Insert         int
 	Delete         int
+	Transpose      *int
 	SpecialReplace int
 	SpecialInsert  int
 }
@@ -35,96 +78,96 @@ type SpecialRunes struct {
 	Insert  rune
 }
 
-func buildPath(n, m int, opCosts *OperationCosts, ops [][]rune, dp [][]int, s1, s2 string, specRunes *SpecialRunes, log *logger.Logger) string {
-	var path []rune
+func buildPath[T comparable](n, m int, costs Costs[T], ops [][]rune, dp [][]int, transFrom map[[2]int][2]int, s1, s2 []T, log Logger) TokenScript[T] {
+	var script TokenScript[T]
+	var none T
 	i, j := n, m
+	tracer, _ := log.(Tracer)
 
-	if log != nil {
-		log.LogMsg("BuildPath", fmt.Sprintf("Start backtracking from (%d, %d)", i, j),
-			logger.ColorCyan)
-	}
+	log.LogMsg("BuildPath", fmt.Sprintf("Start backtracking from (%d, %d)", i, j),
+		logger.ColorCyan)
 
 	for i > 0 || j > 0 {
+		cell, before := Cell{i, j}, len(script)
 		if i > 0 && j > 0 && ops[i][j] == Match {
-			path = append(path, Match)
-			if log != nil {
-				log.LogMsg("BuildPath", fmt.Sprintf("Match at (%d, %d): %c == %c", i, j, s1[i-1], s2[j-1]),
-					logger.ColorGreen)
-			}
+			script = append(script, TokenEdit[T]{Match, i - 1, j - 1, s1[i-1], s2[j-1], matchCost(costs, s1[i-1])})
+			log.LogMsg("BuildPath", fmt.Sprintf("Match at (%d, %d): %v == %v", i, j, s1[i-1], s2[j-1]),
+				logger.ColorGreen)
 			i--
 			j--
 		} else if i > 0 && j > 0 && ops[i][j] == Replace {
-			path = append(path, Replace)
-			if log != nil {
-				log.LogMsg("BuildPath", fmt.Sprintf("Replace at (%d, %d): %c -> %c", i, j, s1[i-1], s2[j-1]),
-					logger.ColorYellow)
-			}
+			script = append(script, TokenEdit[T]{Replace, i - 1, j - 1, s1[i-1], s2[j-1], costs.ReplaceCost(s1[i-1], s2[j-1])})
+			log.LogMsg("BuildPath", fmt.Sprintf("Replace at (%d, %d): %v -> %v", i, j, s1[i-1], s2[j-1]),
+				logger.ColorYellow)
 			i--
 			j--
-		} else if j > 0 && ops[i][j] == Insert {
-			path = append(path, Insert)
-			if log != nil {
-				log.LogMsg("BuildPath", fmt.Sprintf("Insert at (%d, %d): %c", i, j, s2[j-1]),
-					logger.ColorBlue)
+		} else if i > 1 && j > 1 && ops[i][j] == Transpose {
+			from := transFrom[[2]int{i, j}]
+			for k := j - 1; k > from[1]; k-- {
+				script = append(script, TokenEdit[T]{Insert, from[0], k - 1, none, s2[k-1], costs.InsertCost(s2[k-1])})
+			}
+			for k := i - 1; k > from[0]; k-- {
+				script = append(script, TokenEdit[T]{Delete, k - 1, from[1], s1[k-1], none, costs.DeleteCost(s1[k-1])})
 			}
+			script = append(script, TokenEdit[T]{Transpose, from[0] - 1, from[1] - 1, s1[from[0]-1], s1[i-1],
+				transposeCost(costs, s1[from[0]-1], s1[i-1])})
+			log.LogMsg("BuildPath", fmt.Sprintf("Transpose at (%d, %d): %v%v -> %v%v (from (%d, %d))",
+				i, j, s1[from[0]-1], s1[i-1], s2[from[1]-1], s2[j-1], from[0]-1, from[1]-1),
+				logger.ColorPurple)
+			i, j = from[0]-1, from[1]-1
+		} else if j > 0 && ops[i][j] == Insert {
+			script = append(script, TokenEdit[T]{Insert, i, j - 1, none, s2[j-1], costs.InsertCost(s2[j-1])})
+			log.LogMsg("BuildPath", fmt.Sprintf("Insert at (%d, %d): %v", i, j, s2[j-1]),
+				logger.ColorBlue)
 			j--
 		} else if i > 0 && ops[i][j] == Delete {
-			path = append(path, Delete)
-			if log != nil {
-				log.LogMsg("BuildPath", fmt.Sprintf("Delete at (%d, %d): %c", i, j, s1[i-1]),
-					logger.ColorRed)
-			}
+			script = append(script, TokenEdit[T]{Delete, i - 1, j, s1[i-1], none, costs.DeleteCost(s1[i-1])})
+			log.LogMsg("BuildPath", fmt.Sprintf("Delete at (%d, %d): %v", i, j, s1[i-1]),
+				logger.ColorRed)
 			i--
 		} else {
-			replaceCost := opCosts.Replace
-			if i > 0 && j > 0 && rune(s1[i-1]) == specRunes.Replace {
-				replaceCost = opCosts.SpecialReplace
+			replaceTotal, insertTotal, deleteTotal := unreachable, unreachable, unreachable
+			if i > 0 && j > 0 {
+				replaceTotal = dp[i-1][j-1] + costs.ReplaceCost(s1[i-1], s2[j-1])
 			}
-
-			insertCost := opCosts.Insert
-			if j > 0 && rune(s2[j-1]) == specRunes.Insert {
-				insertCost = opCosts.SpecialInsert
-			}
-
-			replaceTotal := dp[i-1][j-1] + replaceCost
-			insertTotal := dp[i][j-1] + insertCost
-			deleteTotal := dp[i-1][j] + opCosts.Delete
-
-			minOp, minCost := Replace, replaceTotal
-			if insertTotal < minCost {
-				minOp, minCost = Insert, insertTotal
+			if j > 0 {
+				insertTotal = dp[i][j-1] + costs.InsertCost(s2[j-1])
 			}
-			if deleteTotal < minCost {
-				minOp, minCost = Delete, deleteTotal
+			if i > 0 {
+				deleteTotal = dp[i-1][j] + costs.DeleteCost(s1[i-1])
 			}
 
-			path = append(path, minOp)
-			if log != nil {
-				log.LogMsg("BuildPath", fmt.Sprintf("Fallback at (%d, %d): chose %c (replace=%d, insert=%d, delete=%d)", i, j, minOp, replaceTotal, insertTotal, deleteTotal),
-					logger.ColorWhite)
-			}
+			minOp, minCost := minOperation(replaceTotal, insertTotal, deleteTotal)
+
+			log.LogMsg("BuildPath", fmt.Sprintf("Fallback at (%d, %d): chose %c (replace=%d, insert=%d, delete=%d)", i, j, minOp, replaceTotal, insertTotal, deleteTotal),
+				logger.ColorWhite)
 
 			switch minOp {
 			case Replace:
+				script = append(script, TokenEdit[T]{Replace, i - 1, j - 1, s1[i-1], s2[j-1], minCost - dp[i-1][j-1]})
 				i--
 				j--
 			case Insert:
+				script = append(script, TokenEdit[T]{Insert, i, j - 1, none, s2[j-1], minCost - dp[i][j-1]})
 				j--
 			case Delete:
+				script = append(script, TokenEdit[T]{Delete, i - 1, j, s1[i-1], none, minCost - dp[i-1][j]})
 				i--
 			}
 		}
-	}
 
-	for k := 0; k < len(path)/2; k++ {
-		path[k], path[len(path)-1-k] = path[len(path)-1-k], path[k]
+		if tracer != nil {
+			traceBacktrack(tracer, cell, Cell{i, j}, script[before:])
+		}
 	}
 
-	if log != nil {
-		log.LogMsg("BuildPath", fmt.Sprintf("Final path: %s", string(path)), logger.ColorGreen)
+	for k := 0; k < len(script)/2; k++ {
+		script[k], script[len(script)-1-k] = script[len(script)-1-k], script[k]
 	}
 
-	return string(path)
+	log.LogMsg("BuildPath", fmt.Sprintf("Final path: %v", script), logger.ColorGreen)
+
+	return script
 }
 
 func minOperation(replaceTotal, insertTotal, deleteTotal int) (rune, int) {
@@ -143,8 +186,69 @@ func minOperation(replaceTotal, insertTotal, deleteTotal int) (rune, int) {
 	return minOp, minCost
 }
 
-func FindLevenshteinDistance(s1, s2 string, opCosts *OperationCosts, specRunes *SpecialRunes, log *logger.Logger) (int, string) {
-	n, m := len(s1), len(s2)
+func FindLevenshteinDistance(s1, s2 string, costs CostModel, log Logger) (int, string) {
+	return FindLevenshteinDistanceSegmented(s1, s2, Runes, costs, log)
+}
+
+func FindLevenshteinDistanceSegmented(s1, s2 string, seg Segmentation, costs CostModel, log Logger) (int, string) {
+	return FindDamerauLevenshteinDistance(s1, s2, seg, NoTransposition, costs, log)
+}
+
+// The unrestricted variant is the Lowrance-Wagner algorithm and is only exact
+// when two transpositions never cost less than an insert plus a delete,
+// 2*Transpose >= Insert+Delete. With cheaper transpositions it misses scripts
+// that swap a symbol more than once: with replace 10, insert 5, delete 5 and
+// transpose 1 it prices "abc" -> "bca" at 10 instead of 2.
+func FindDamerauLevenshteinDistance(s1, s2 string, seg Segmentation, variant Transposition, costs CostModel, log Logger) (int, string) {
+	return findDistance(Split(s1, seg), Split(s2, seg), variant, costs, log)
+}
+
+func FindEditScript(s1, s2 string, seg Segmentation, variant Transposition, costs CostModel, log Logger) (int, EditScript) {
+	distance, script := findScript(Split(s1, seg), Split(s2, seg), variant, costs, log)
+	return distance, EditScript(script)
+}
+
+// Distance compares arbitrary token sequences, such as the words of two
+// sentences or the lines of two files, with the same DP and tie order as
+// FindLevenshteinDistance.
+func Distance[T comparable](a, b []T, costs Costs[T]) (int, TokenScript[T]) {
+	return FindTokenEditScript(a, b, NoTransposition, costs, discardLogger)
+}
+
+func FindTokenEditScript[T comparable](a, b []T, variant Transposition, costs Costs[T], log Logger) (int, TokenScript[T]) {
+	return findScript(a, b, variant, costs, log)
+}
+
+func findDistance[T comparable](a, b []T, variant Transposition, costs Costs[T], log Logger) (int, string) {
+	distance, script := findScript(a, b, variant, costs, log)
+	return distance, script.String()
+}
+
+func findScript[T comparable](a, b []T, variant Transposition, costs Costs[T], log Logger) (int, TokenScript[T]) {
+	log, costs = orDiscard(log), orUnit(costs)
+	if variant == NoTransposition && !debugging(log) && hasUnitCosts(a, b, costs) {
+		distance, path := myersPath(a, b, log)
+		return distance, scriptFromPath(a, b, path, costs)
+	}
+
+	n, m := len(a), len(b)
+	dp, ops, transFrom := fillMatrices(a, b, variant, costs, log)
+
+	script := buildPath(n, m, costs, ops, dp, transFrom, a, b, log)
+	if tracer, ok := log.(Tracer); ok {
+		tracer.TraceEvent(TraceEvent{Kind: EventResult, I: n, J: m, Cost: dp[n][m], Path: script.String()})
+	}
+	log.LogMsg("Result", fmt.Sprintf("Final distance: %d, Path: %s", dp[n][m], script),
+		logger.ColorGreen)
+
+	return dp[n][m], script
+}
+
+func fillMatrices[T comparable](a, b []T, variant Transposition, costs Costs[T], log Logger) ([][]int, [][]rune, map[[2]int][2]int) {
+	n, m := len(a), len(b)
+	special, _ := any(costs).(specialRuneModel)
+	tracer, _ := log.(Tracer)
+	debug := debugging(log)
 
 	dp := make([][]int, n+1)
 	ops := make([][]rune, n+1)
@@ -153,74 +257,165 @@ func FindLevenshteinDistance(s1, s2 string, opCosts *OperationCosts, specRunes *
 		ops[i] = make([]rune, m+1)
 	}
 
-	log.LogMsg("Init", fmt.Sprintf("Calculating distance between '%s' (%d) and '%s' (%d)", s1, n, s2, m),
-		logger.ColorCyan)
-	log.LogMsg("Costs", fmt.Sprintf("Replace: %d, Insert: %d, Delete: %d, SpecialReplace: %d, SpecialInsert: %d",
-		opCosts.Replace, opCosts.Insert, opCosts.Delete, opCosts.SpecialReplace, opCosts.SpecialInsert),
-		logger.ColorCyan)
-	log.LogMsg("SpecialRunes", fmt.Sprintf("Replace: %c, Insert: %c", specRunes.Replace, specRunes.Insert),
+	transFrom := make(map[[2]int][2]int)
+	lastRow := make(map[T]int)
+	insertPrefix := make([]int, m+1)
+	for j := 1; j <= m; j++ {
+		insertPrefix[j] = insertPrefix[j-1] + costs.InsertCost(b[j-1])
+	}
+	deletePrefix := make([]int, n+1)
+	for i := 1; i <= n; i++ {
+		deletePrefix[i] = deletePrefix[i-1] + costs.DeleteCost(a[i-1])
+	}
+
+	log.LogMsg("Init", fmt.Sprintf("Calculating distance between '%s' (%d) and '%s' (%d)", joinSymbols(a), n, joinSymbols(b), m),
 		logger.ColorCyan)
+	log.LogMsg("Costs", fmt.Sprint(costs), logger.ColorCyan)
+	if tracer != nil {
+		tracer.TraceEvent(TraceEvent{Kind: EventInit, Source: symbolTexts(a), Target: symbolTexts(b)})
+	}
 
 	dp[0][0] = 0
 	ops[0][0] = Match
+	if tracer != nil {
+		traceCell(tracer, 0, 0, Match, 0)
+	}
 
 	for j := 1; j <= m; j++ {
-		dp[0][j] = dp[0][j-1] + opCosts.Insert
+		dp[0][j] = insertPrefix[j]
 		ops[0][j] = Insert
+		if tracer != nil {
+			traceCell(tracer, 0, j, Insert, dp[0][j], Candidate{string(Insert), dp[0][j]})
+		}
 	}
 
 	for i := 1; i <= n; i++ {
-		dp[i][0] = dp[i-1][0] + opCosts.Delete
+		dp[i][0] = deletePrefix[i]
 		ops[i][0] = Delete
+		if tracer != nil {
+			traceCell(tracer, i, 0, Delete, dp[i][0], Candidate{string(Delete), dp[i][0]})
+		}
 	}
 
 	log.LogCostMatrix("Initial DP", dp, logger.ColorRed)
 	log.LogRuneMatrix("Initial Ops", ops, logger.ColorBlue)
 
 	for i := 1; i <= n; i++ {
+		lastCol := 0
 		for j := 1; j <= m; j++ {
-			if s1[i-1] == s2[j-1] {
-				dp[i][j] = dp[i-1][j-1]
-				ops[i][j] = Match
-				log.LogMsg("Match", fmt.Sprintf("Characters match at (%d,%d): %c", i, j, s1[i-1]),
-					logger.ColorGreen)
+			if a[i-1] == b[j-1] {
+				matchTotal := dp[i-1][j-1] + matchCost(costs, a[i-1])
+				insertTotal := dp[i][j-1] + costs.InsertCost(b[j-1])
+				deleteTotal := dp[i-1][j] + costs.DeleteCost(a[i-1])
+				minOp, minCost := minOperation(matchTotal, insertTotal, deleteTotal)
+				if minOp == Replace {
+					minOp = Match
+				}
+				dp[i][j] = minCost
+				ops[i][j] = minOp
+				lastCol = j
+				if tracer != nil {
+					traceCell(tracer, i, j, minOp, minCost, Candidate{string(Match), matchTotal},
+						Candidate{string(Insert), insertTotal}, Candidate{string(Delete), deleteTotal})
+				}
+				if debug {
+					log.LogMsg("Match", fmt.Sprintf("Characters match at (%d,%d): %v, chose %c with cost %d", i, j, a[i-1], minOp, minCost),
+						logger.ColorGreen)
+				}
 			} else {
-				replaceCost := opCosts.Replace
-				if rune(s1[i-1]) == specRunes.Replace {
-					replaceCost = opCosts.SpecialReplace
-					log.LogMsg("SpecialReplace", fmt.Sprintf("Special replace at (%d,%d): %c", i, j, s1[i-1]),
-						logger.ColorPurple)
+				if special != nil && isSpecial(special.isSpecialReplace, a[i-1]) {
+					if debug {
+						log.LogMsg("SpecialReplace", fmt.Sprintf("Special replace at (%d,%d): %v", i, j, a[i-1]),
+							logger.ColorPurple)
+					}
+					if tracer != nil {
+						tracer.TraceEvent(TraceEvent{Kind: EventSpecial, I: i, J: j, Op: string(Replace),
+							Cost: costs.ReplaceCost(a[i-1], b[j-1]), Symbol: symbolText(a[i-1])})
+					}
 				}
-
-				insertCost := opCosts.Insert
-				if rune(s2[j-1]) == specRunes.Insert {
-					insertCost = opCosts.SpecialInsert
-					log.LogMsg("SpecialInsert", fmt.Sprintf("Special insert at (%d,%d): %c", i, j, s2[j-1]),
-						logger.ColorPurple)
+				if special != nil && isSpecial(special.isSpecialInsert, b[j-1]) {
+					if debug {
+						log.LogMsg("SpecialInsert", fmt.Sprintf("Special insert at (%d,%d): %v", i, j, b[j-1]),
+							logger.ColorPurple)
+					}
+					if tracer != nil {
+						tracer.TraceEvent(TraceEvent{Kind: EventSpecial, I: i, J: j, Op: string(Insert),
+							Cost: costs.InsertCost(b[j-1]), Symbol: symbolText(b[j-1])})
+					}
 				}
 
-				replaceTotal := dp[i-1][j-1] + replaceCost
-				insertTotal := dp[i][j-1] + insertCost
-				deleteTotal := dp[i-1][j] + opCosts.Delete
+				replaceTotal := dp[i-1][j-1] + costs.ReplaceCost(a[i-1], b[j-1])
+				insertTotal := dp[i][j-1] + costs.InsertCost(b[j-1])
+				deleteTotal := dp[i-1][j] + costs.DeleteCost(a[i-1])
 
 				minOp, minCost := minOperation(replaceTotal, insertTotal, deleteTotal)
+				var candidates []Candidate
+				if tracer != nil {
+					candidates = []Candidate{{string(Replace), replaceTotal}, {string(Insert), insertTotal},
+						{string(Delete), deleteTotal}}
+				}
+
+				k, l := 0, 0
+				switch variant {
+				case OptimalStringAlignment:
+					if i > 1 && j > 1 && a[i-1] == b[j-2] && a[i-2] == b[j-1] {
+						k, l = i-1, j-1
+					}
+				case UnrestrictedTransposition:
+					k, l = lastRow[b[j-1]], lastCol
+				}
+
+				if k > 0 && l > 0 {
+					transposeTotal := dp[k-1][l-1] + deletePrefix[i-1] - deletePrefix[k] +
+						transposeCost(costs, a[k-1], a[i-1]) + insertPrefix[j-1] - insertPrefix[l]
+					if debug {
+						log.LogMsg("Transpose", fmt.Sprintf("Transposition at (%d,%d) from (%d,%d): cost %d",
+							i, j, k-1, l-1, transposeTotal),
+							logger.ColorPurple)
+					}
+					if tracer != nil {
+						candidates = append(candidates, Candidate{string(Transpose), transposeTotal})
+					}
+					if transposeTotal < minCost {
+						minOp, minCost = Transpose, transposeTotal
+						transFrom[[2]int{i, j}] = [2]int{k, l}
+					}
+				}
 
 				ops[i][j] = minOp
 				dp[i][j] = minCost
+				if tracer != nil {
+					traceCell(tracer, i, j, minOp, minCost, candidates...)
+				}
 
-				log.LogMsg("Operation", fmt.Sprintf("Cell (%d,%d): chose %c with cost %d (replace=%d, insert=%d, delete=%d)",
-					i, j, minOp, minCost, replaceTotal, insertTotal, deleteTotal),
-					logger.ColorYellow)
+				if debug {
+					log.LogMsg("Operation", fmt.Sprintf("Cell (%d,%d): chose %c with cost %d (replace=%d, insert=%d, delete=%d)",
+						i, j, minOp, minCost, replaceTotal, insertTotal, deleteTotal),
+						logger.ColorYellow)
+				}
 			}
 		}
+		lastRow[a[i-1]] = i
 	}
 
 	log.LogCostMatrix("Final DP", dp, logger.ColorRed)
 	log.LogRuneMatrix("Final Ops", ops, logger.ColorBlue)
 
-	path := buildPath(n, m, opCosts, ops, dp, s1, s2, specRunes, log)
-	log.LogMsg("Result", fmt.Sprintf("Final distance: %d, Path: %s", dp[n][m], path),
-		logger.ColorGreen)
+	return dp, ops, transFrom
+}
+
+func traceCell(tracer Tracer, i, j int, op rune, cost int, candidates ...Candidate) {
+	tracer.TraceEvent(TraceEvent{Kind: EventCell, I: i, J: j, Op: string(op), Cost: cost, Candidates: candidates})
+}
+
+func joinSymbols[T comparable](symbols []T) string {
+	if strs, ok := any(symbols).([]string); ok {
+		return strings.Join(strs, "")
+	}
+	return fmt.Sprint(symbols)
+}
 
-	return dp[n][m], path
+func isSpecial[T comparable](check func(string) bool, symbol T) bool {
+	s, ok := any(symbol).(string)
+	return ok && check(s)
 }
@@ -0,0 +1,32 @@
+package vagner_fisher
+
+import "testing"
+
+// titleLogger counts the messages of every title.
+type titleLogger struct {
+	enabled bool
+	titles  map[string]int
+}
+
+func (l *titleLogger) LogMsg(title, message, color string)                     { l.titles[title]++ }
+func (l *titleLogger) LogRuneMatrix(title string, data [][]rune, color string) {}
+func (l *titleLogger) LogCostMatrix(title string, data [][]int, color string)  {}
+func (l *titleLogger) Enabled() bool                                           { return l.enabled }
+
+func TestCellMessagesOnlyWhenDebugging(t *testing.T) {
+	costs := NewClassicCosts(&OperationCosts{Replace: 1, Insert: 1, Delete: 1, SpecialReplace: 2, SpecialInsert: 2},
+		&SpecialRunes{Replace: 'a', Insert: 'c'})
+	cellTitles := []string{"Match", "Operation", "Transpose", "SpecialReplace", "SpecialInsert"}
+
+	for _, enabled := range []bool{false, true} {
+		log := &titleLogger{enabled: enabled, titles: make(map[string]int)}
+		if got, _ := FindEditScript("abcab", "bacba", Runes, OptimalStringAlignment, costs, log); got != 2 {
+			t.Errorf("enabled %t: distance = %d, want 2", enabled, got)
+		}
+		for _, title := range cellTitles {
+			if logged := log.titles[title] > 0; logged != enabled {
+				t.Errorf("enabled %t: logged %d %s messages", enabled, log.titles[title], title)
+			}
+		}
+	}
+}